@@ -0,0 +1,219 @@
+// Package armcompute lets skewer caches be populated by clients of the
+// track-2 Azure SDK. It is a separate module so that the root module
+// does not require the track-2 SDK or a newer Go version.
+package armcompute
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	arm "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/alexeldeib/skewer"
+)
+
+// PagerClient is the track-2 Azure SDK client interface which may be
+// used to populate skewer's data. It is satisfied by
+// *armcompute.ResourceSKUsClient of the track-2 SDK.
+type PagerClient interface {
+	NewListPager(options *arm.ResourceSKUsClientListOptions) *runtime.Pager[arm.ResourceSKUsClientListResponse]
+}
+
+// WithPagerClient is a functional option to use a cache backed by a
+// track-2 PagerClient. Retries are left to the client's own pipeline,
+// so WithRetryPolicy does not apply.
+func WithPagerClient(client PagerClient) skewer.CacheOption {
+	return skewer.WithClient(newWrappedPagerClient(client))
+}
+
+// wrappedPagerClient defines a wrapper for the track-2 Azure client
+// signature to collect all resource skus from the pager returned by
+// NewListPager(), converted to the track-1 model.
+type wrappedPagerClient struct {
+	client PagerClient
+}
+
+func newWrappedPagerClient(client PagerClient) *wrappedPagerClient {
+	return &wrappedPagerClient{client}
+}
+
+// List greedily traverses all returned sku pages. On failure, the skus
+// from all pages before the failed one are returned alongside an
+// *skewer.ErrListResourceSkus.
+func (w *wrappedPagerClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+	options := &arm.ResourceSKUsClientListOptions{}
+	if filter != "" {
+		options.Filter = &filter
+	}
+
+	var skus []compute.ResourceSku
+	pager := w.client.NewListPager(options)
+	for page := 0; pager.More(); page++ {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			stage := skewer.StageNextPage
+			if page == 0 {
+				stage = skewer.StageList
+			}
+			return skus, &skewer.ErrListResourceSkus{Stage: stage, Page: page, Err: err}
+		}
+		for _, sku := range resp.Value {
+			if sku != nil {
+				skus = append(skus, convertResourceSKU(sku))
+			}
+		}
+	}
+
+	return skus, nil
+}
+
+// WrapResourceSKUs takes an array of track-2 armcompute resource skus
+// and converts them into an array of skewer's richer type. Nil entries
+// are skipped.
+func WrapResourceSKUs(in []*arm.ResourceSKU) []skewer.SKU {
+	out := make([]skewer.SKU, 0, len(in))
+	for _, sku := range in {
+		if sku != nil {
+			out = append(out, skewer.SKU(convertResourceSKU(sku)))
+		}
+	}
+	return out
+}
+
+// convertResourceSKU maps a track-2 resource sku onto the track-1 model.
+// Nil slices stay nil, empty slices stay empty, and nil elements are
+// dropped.
+func convertResourceSKU(in *arm.ResourceSKU) compute.ResourceSku {
+	out := compute.ResourceSku{
+		ResourceType: in.ResourceType,
+		Name:         in.Name,
+		Tier:         in.Tier,
+		Size:         in.Size,
+		Family:       in.Family,
+		Kind:         in.Kind,
+		Locations:    convertStrings(in.Locations),
+		APIVersions:  convertStrings(in.APIVersions),
+		Capabilities: convertCapabilities(in.Capabilities),
+	}
+
+	if in.Capacity != nil {
+		out.Capacity = &compute.ResourceSkuCapacity{
+			Minimum: in.Capacity.Minimum,
+			Maximum: in.Capacity.Maximum,
+			Default: in.Capacity.Default,
+		}
+		if in.Capacity.ScaleType != nil {
+			out.Capacity.ScaleType = compute.ResourceSkuCapacityScaleType(*in.Capacity.ScaleType)
+		}
+	}
+
+	if in.Costs != nil {
+		costs := make([]compute.ResourceSkuCosts, 0, len(in.Costs))
+		for _, cost := range in.Costs {
+			if cost != nil {
+				costs = append(costs, compute.ResourceSkuCosts{
+					MeterID:      cost.MeterID,
+					Quantity:     cost.Quantity,
+					ExtendedUnit: cost.ExtendedUnit,
+				})
+			}
+		}
+		out.Costs = &costs
+	}
+
+	if in.LocationInfo != nil {
+		locationInfo := make([]compute.ResourceSkuLocationInfo, 0, len(in.LocationInfo))
+		for _, info := range in.LocationInfo {
+			if info != nil {
+				locationInfo = append(locationInfo, convertLocationInfo(info))
+			}
+		}
+		out.LocationInfo = &locationInfo
+	}
+
+	if in.Restrictions != nil {
+		restrictions := make([]compute.ResourceSkuRestrictions, 0, len(in.Restrictions))
+		for _, restriction := range in.Restrictions {
+			if restriction != nil {
+				restrictions = append(restrictions, convertRestriction(restriction))
+			}
+		}
+		out.Restrictions = &restrictions
+	}
+
+	return out
+}
+
+func convertLocationInfo(in *arm.ResourceSKULocationInfo) compute.ResourceSkuLocationInfo {
+	out := compute.ResourceSkuLocationInfo{
+		Location: in.Location,
+		Zones:    convertStrings(in.Zones),
+	}
+
+	if in.ZoneDetails != nil {
+		zoneDetails := make([]compute.ResourceSkuZoneDetails, 0, len(in.ZoneDetails))
+		for _, details := range in.ZoneDetails {
+			if details != nil {
+				zoneDetails = append(zoneDetails, compute.ResourceSkuZoneDetails{
+					Name:         convertStrings(details.Name),
+					Capabilities: convertCapabilities(details.Capabilities),
+				})
+			}
+		}
+		out.ZoneDetails = &zoneDetails
+	}
+
+	return out
+}
+
+func convertRestriction(in *arm.ResourceSKURestrictions) compute.ResourceSkuRestrictions {
+	out := compute.ResourceSkuRestrictions{
+		Values: convertStrings(in.Values),
+	}
+
+	if in.Type != nil {
+		out.Type = compute.ResourceSkuRestrictionsType(*in.Type)
+	}
+
+	if in.ReasonCode != nil {
+		out.ReasonCode = compute.ResourceSkuRestrictionsReasonCode(*in.ReasonCode)
+	}
+
+	if in.RestrictionInfo != nil {
+		out.RestrictionInfo = &compute.ResourceSkuRestrictionInfo{
+			Locations: convertStrings(in.RestrictionInfo.Locations),
+			Zones:     convertStrings(in.RestrictionInfo.Zones),
+		}
+	}
+
+	return out
+}
+
+func convertCapabilities(in []*arm.ResourceSKUCapabilities) *[]compute.ResourceSkuCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := make([]compute.ResourceSkuCapabilities, 0, len(in))
+	for _, capability := range in {
+		if capability != nil {
+			out = append(out, compute.ResourceSkuCapabilities{
+				Name:  capability.Name,
+				Value: capability.Value,
+			})
+		}
+	}
+	return &out
+}
+
+func convertStrings(in []*string) *[]string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, 0, len(in))
+	for _, value := range in {
+		if value != nil {
+			out = append(out, *value)
+		}
+	}
+	return &out
+}
@@ -0,0 +1,182 @@
+package armcompute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	arm "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/alexeldeib/skewer"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// fakePagerClient serves pages of track-2 skus, optionally failing a page.
+type fakePagerClient struct {
+	pages   [][]*arm.ResourceSKU
+	failAt  int
+	err     error
+	filters []string
+}
+
+func (f *fakePagerClient) NewListPager(options *arm.ResourceSKUsClientListOptions) *runtime.Pager[arm.ResourceSKUsClientListResponse] {
+	filter := ""
+	if options != nil && options.Filter != nil {
+		filter = *options.Filter
+	}
+	f.filters = append(f.filters, filter)
+
+	cursor := 0
+	return runtime.NewPager(runtime.PagingHandler[arm.ResourceSKUsClientListResponse]{
+		More: func(arm.ResourceSKUsClientListResponse) bool {
+			return cursor < len(f.pages)
+		},
+		Fetcher: func(context.Context, *arm.ResourceSKUsClientListResponse) (arm.ResourceSKUsClientListResponse, error) {
+			if f.err != nil && cursor == f.failAt {
+				return arm.ResourceSKUsClientListResponse{}, f.err
+			}
+			page := f.pages[cursor]
+			cursor++
+			return arm.ResourceSKUsClientListResponse{
+				ResourceSKUsResult: arm.ResourceSKUsResult{Value: page},
+			}, nil
+		},
+	})
+}
+
+func newTrack2DataWrapper(t *testing.T, path string) []*arm.ResourceSKU {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result arm.ResourceSKUsResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result.Value
+}
+
+func chunkTrack2(skus []*arm.ResourceSKU, count int) [][]*arm.ResourceSKU {
+	divided := [][]*arm.ResourceSKU{}
+	size := (len(skus) + count - 1) / count
+	for i := 0; i < len(skus); i += size {
+		end := i + size
+		if end > len(skus) {
+			end = len(skus)
+		}
+		divided = append(divided, skus[i:end])
+	}
+	return divided
+}
+
+// newTrack1DataWrapper reads a list of skus with the track-1 decoder.
+func newTrack1DataWrapper(t *testing.T, path string) []compute.ResourceSku {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result compute.ResourceSkusResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return *result.Value
+}
+
+// fakeClient is a client meeting the skewer signature, listing nothing.
+type fakeClient struct{}
+
+func (fakeClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+	return nil, nil
+}
+
+func Test_WrapResourceSKUs(t *testing.T) {
+	track1 := newTrack1DataWrapper(t, "../testdata/eastus.json")
+	track2 := newTrack2DataWrapper(t, "../testdata/eastus.json")
+
+	// The fixture spells the zone detail name key "Name", which the
+	// case-sensitive track-2 decoder does not recognise, so that one
+	// field is excluded from the comparison.
+	ignoreZoneDetailNames := cmpopts.IgnoreFields(compute.ResourceSkuZoneDetails{}, "Name")
+	if diff := cmp.Diff(skewer.Wrap(track1), WrapResourceSKUs(append(track2, nil)), ignoreZoneDetailNames); diff != "" {
+		t.Errorf("expected track-2 conversion to match track-1 model: %s", diff)
+	}
+}
+
+func Test_WithPagerClient(t *testing.T) {
+	ctx := context.Background()
+	track2 := newTrack2DataWrapper(t, "../testdata/eastus.json")
+	expectedVirtualMachinesCount := len(skewer.Filter(WrapResourceSKUs(track2), skewer.ResourceTypeFilter(skewer.VirtualMachines)))
+
+	t.Run("should list every page", func(t *testing.T) {
+		client := &fakePagerClient{pages: chunkTrack2(track2, 10)}
+		cache, err := skewer.NewCache(ctx, WithPagerClient(client), skewer.WithLocation("eastus"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"location eq 'eastus'"}, client.filters); diff != "" {
+			t.Errorf("expected location filter to be passed to pager: %s", diff)
+		}
+		if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+			t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+		}
+		sku, found := cache.Get(ctx, "standard_d4s_v3", skewer.VirtualMachines)
+		if !found {
+			t.Fatalf("expected to find virtual machine sku standard_d4s_v3")
+		}
+		if cpu, err := sku.VCPU(); cpu != 4 || err != nil {
+			t.Errorf("expected standard_d4s_v3 to have 4 vCPUs and parse successfully, got value '%d' and error '%s'", cpu, err)
+		}
+	})
+
+	t.Run("should not accept a second client", func(t *testing.T) {
+		errClientNotNil := &skewer.ErrClientNotNil{}
+		_, err := skewer.NewCache(ctx, skewer.WithClient(fakeClient{}), WithPagerClient(&fakePagerClient{}))
+		if !errors.As(err, &errClientNotNil) {
+			t.Errorf("expected ErrClientNotNil, got: %v", err)
+		}
+	})
+
+	cases := map[string]struct {
+		failAt int
+		stage  skewer.ListStage
+	}{
+		"failing first page": {
+			failAt: 0,
+			stage:  skewer.StageList,
+		},
+		"failing later page": {
+			failAt: 4,
+			stage:  skewer.StageNextPage,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			boom := errors.New("boom")
+			client := &fakePagerClient{pages: chunkTrack2(track2, 10), failAt: tc.failAt, err: boom}
+			skus, err := newWrappedPagerClient(client).List(ctx, "")
+			errList := &skewer.ErrListResourceSkus{}
+			if !errors.As(err, &errList) || errList.Stage != tc.stage || errList.Page != tc.failAt || !errors.Is(err, boom) {
+				t.Fatalf("expected failure at stage '%s' page %d, got: %v", tc.stage, tc.failAt, err)
+			}
+			if expected := len(skewer.Filter(skewer.Wrap(skusBefore(client.pages, tc.failAt)))); len(skus) != expected {
+				t.Errorf("expected %d skus before the failed page, got %d", expected, len(skus))
+			}
+		})
+	}
+}
+
+func skusBefore(pages [][]*arm.ResourceSKU, page int) []compute.ResourceSku {
+	var skus []compute.ResourceSku
+	for i := 0; i < page; i++ {
+		for _, sku := range pages[i] {
+			skus = append(skus, convertResourceSKU(sku))
+		}
+	}
+	return skus
+}
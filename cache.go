@@ -4,14 +4,76 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Cache stores a list of known skus, possibly fetched with a provided client
+// Cache stores a list of known skus, possibly fetched with a provided
+// client. All methods are safe for concurrent use. The sku slice held by
+// the cache is never mutated in place: refreshes swap in a new slice, so
+// readers may keep using a slice obtained under the read lock after
+// releasing it.
 type Cache struct {
 	location string
 	filter   string
 	client   client
-	data     []SKU
+
+	// ttl is the maximum age of data before queries trigger a refresh.
+	// Zero disables staleness checks.
+	ttl                  time.Duration
+	staleWhileRevalidate bool
+	revalidating         int32
+	// revalidateBackoff is the least time between inline refreshes
+	// after one fails, and revalidateTimeout bounds background ones.
+	// Both are derived from ttl.
+	revalidateBackoff time.Duration
+	revalidateTimeout time.Duration
+
+	// background refresh configuration and lifecycle, see refresher.go.
+	refreshInterval time.Duration
+	refreshJitter   time.Duration
+	refresherMu     sync.Mutex
+	refresherCancel context.CancelFunc
+	refresherDone   chan struct{}
+	refresherRand   *lockedRand
+
+	flights flightGroup
+
+	// retry configures retries of transient failures for clients which
+	// list skus with Azure iterators.
+	retry *RetryPolicy
+
+	partialResults bool
+
+	// interner, when set, stores data in compact form, see compact.go.
+	// held holds the interned entries of data.
+	interner *interner
+	held     []*internEntry
+
+	// watchers receive changes to data, see watch.go. published counts
+	// the updates of data whose events were sent, and watchTurn signals
+	// when it changes.
+	watchMu   sync.Mutex
+	watchTurn sync.Cond
+	watchers  map[*watcher]struct{}
+	published uint64
+
+	mu        sync.RWMutex
+	data      []SKU
+	index     *skuIndex
+	fetchedAt time.Time
+	// failedAt is the time of the last failed refresh.
+	failedAt time.Time
+	// incomplete is non-nil when data came from an interrupted listing.
+	incomplete *Completeness
+	// generations counts the loads of data started, and dataGeneration
+	// is the number of the load which stored data, so loads finishing
+	// out of order cannot replace data with older data.
+	generations    uint64
+	dataGeneration uint64
+	// updates counts the replacements of data, to order their events.
+	updates uint64
 }
 
 // CacheOption describes functional options to customize the listing behavior of the cache.
@@ -26,6 +88,48 @@ func WithLocation(location string) CacheOption {
 	}
 }
 
+// WithTTL is a functional option to bound the age of cached data. When
+// the data is older than ttl, queries against a cache with a client
+// will refresh it before answering. Failed refreshes leave the stale
+// data in place, and queries do not refresh it again until the lesser
+// of ttl and 30 seconds has passed, so an outage does not make every
+// query wait for a failing listing.
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) error {
+		c.ttl = ttl
+		c.revalidateBackoff = ttl
+		if c.revalidateBackoff > maxRevalidateBackoff {
+			c.revalidateBackoff = maxRevalidateBackoff
+		}
+		c.revalidateTimeout = ttl
+		if c.revalidateTimeout < minRevalidateTimeout {
+			c.revalidateTimeout = minRevalidateTimeout
+		}
+		return nil
+	}
+}
+
+const (
+	// maxRevalidateBackoff bounds the time queries wait after a failed
+	// refresh before refreshing stale data inline again.
+	maxRevalidateBackoff = 30 * time.Second
+	// minRevalidateTimeout is the least time background refreshes are
+	// given, so caches with short TTLs can still list all skus.
+	minRevalidateTimeout = time.Minute
+)
+
+// WithStaleWhileRevalidate is a functional option which changes the
+// behavior of WithTTL: queries against stale data return immediately
+// with the stale data, while a single refresh runs in the background.
+// Background refreshes are cancelled after the greater of the TTL and
+// one minute.
+func WithStaleWhileRevalidate() CacheOption {
+	return func(c *Cache) error {
+		c.staleWhileRevalidate = true
+		return nil
+	}
+}
+
 // ErrClientNil will be returned when a user attempts to create a cache
 // without a client and use it.
 type ErrClientNil struct {
@@ -92,17 +196,15 @@ type NewCacheFunc func(ctx context.Context, opts ...CacheOption) (*Cache, error)
 func NewCache(ctx context.Context, opts ...CacheOption) (*Cache, error) {
 	c := &Cache{}
 
-	for _, optionFn := range opts {
-		if err := optionFn(c); err != nil {
-			return nil, err
-		}
+	if err := c.applyOptions(opts); err != nil {
+		return nil, err
 	}
 
 	if c.client == nil {
 		return nil, &ErrClientNil{}
 	}
 
-	if err := c.refresh(ctx); err != nil {
+	if result, err := c.Refresh(ctx); err != nil && !result.Partial {
 		return nil, err
 	}
 
@@ -111,77 +213,243 @@ func NewCache(ctx context.Context, opts ...CacheOption) (*Cache, error) {
 
 // NewStaticCache initializes a cache with data and no ability to refresh. Used for testing.
 func NewStaticCache(data []SKU, opts ...CacheOption) (*Cache, error) {
-	c := &Cache{
-		data: data,
+	c := &Cache{}
+
+	if err := c.applyOptions(opts); err != nil {
+		return nil, err
 	}
 
+	c.replaceData(c.prepareData(data))
+
+	return c, nil
+}
+
+// applyOptions applies functional options in order, then wires up
+// settings which depend on more than one option.
+func (c *Cache) applyOptions(opts []CacheOption) error {
 	for _, optionFn := range opts {
 		if err := optionFn(c); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return c, nil
+	if wrapped, ok := c.client.(*wrappedResourceClient); ok && c.retry != nil {
+		wrapped.retry = *c.retry
+	}
+
+	return nil
+}
+
+// RefreshResult describes the outcome of a single call to Refresh.
+type RefreshResult struct {
+	// Duration is the wall clock time spent fetching data.
+	Duration time.Duration
+	// Count is the number of skus stored in the cache after the refresh.
+	// On failure, it is the number of skus retained from the previous
+	// successful refresh.
+	Count int
+	// Err is the error encountered while fetching, if any.
+	Err error
+	// Partial is true when the refresh failed, but the skus collected
+	// before the failure were kept. See WithPartialResults.
+	Partial bool
 }
 
-func (c *Cache) refresh(ctx context.Context) error {
+// Refresh fetches the latest resource sku data using the configured
+// client. The cached data is replaced only when the fetch succeeds; on
+// failure the previous data is kept intact and the error is returned
+// alongside the result. Concurrent calls are coalesced: while a refresh
+// is in flight, other callers wait
+// for it and share its result instead of listing skus again. Each caller
+// stops waiting when its own ctx is done; the shared refresh continues
+// for the remaining callers, and is cancelled once none are waiting.
+func (c *Cache) Refresh(ctx context.Context) (RefreshResult, error) {
+	if c.client == nil {
+		err := &ErrClientNil{}
+		return RefreshResult{Count: len(c.skus()), Err: err}, err
+	}
+
+	value, _, err := c.flights.do(ctx, refreshFlight, func(ctx context.Context) (interface{}, error) {
+		return c.refresh(ctx)
+	})
+	if value == nil {
+		// The caller's context was done before the refresh completed.
+		return RefreshResult{Count: len(c.skus()), Err: err}, err
+	}
+	return value.(RefreshResult), err
+}
+
+// refreshFlight is the key of refreshes in the flight group of a cache,
+// which only coalesces refreshes.
+const refreshFlight = "refresh"
+
+// nextGeneration returns the number of a new load of data.
+func (c *Cache) nextGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations++
+	return c.generations
+}
+
+func (c *Cache) refresh(ctx context.Context) (RefreshResult, error) {
+	generation := c.nextGeneration()
+	start := time.Now()
 	data, err := c.client.List(ctx, c.filter)
+	result := RefreshResult{
+		Duration: time.Since(start),
+	}
 	if err != nil {
-		return err
+		// Refreshes cancelled because no caller waits still back off:
+		// a listing slower than the deadlines of its callers would
+		// otherwise be retried by every query.
+		c.mu.Lock()
+		c.failedAt = time.Now()
+		c.mu.Unlock()
+		// Partial data leaves fetchedAt untouched, so caches with a TTL
+		// keep trying to complete it. Data cut short only because no
+		// caller waits is not kept.
+		if ctx.Err() == nil {
+			result.Partial = c.keepPartial(data, err, generation)
+		}
+		result.Count = len(c.skus())
+		result.Err = err
+		return result, err
 	}
 
-	c.data = Wrap(data)
+	c.storeComplete(Wrap(data), start, generation)
+	result.Count = len(c.skus())
 
-	return nil
+	return result, nil
+}
+
+// IsStale returns true when the cache has a TTL and its data is older
+// than the TTL.
+func (c *Cache) IsStale() bool {
+	if c.ttl <= 0 {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Since(c.fetchedAt) > c.ttl
+}
+
+// FetchedAt returns the time the cached data was fetched. It is the
+// zero time for static caches.
+func (c *Cache) FetchedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fetchedAt
+}
+
+// revalidate refreshes stale data before queries, either inline or in
+// the background depending on configuration. Errors are dropped: the
+// caller is served the previous data.
+func (c *Cache) revalidate(ctx context.Context) {
+	if c.client == nil || !c.IsStale() {
+		return
+	}
+
+	if !c.staleWhileRevalidate {
+		if c.backingOff() {
+			return
+		}
+		_, _ = c.Refresh(ctx)
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&c.revalidating, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&c.revalidating, 0)
+		// The query context may end long before the refresh does, so
+		// the refresh gets its own deadline.
+		ctx, cancel := context.WithTimeout(context.Background(), c.revalidateTimeout)
+		defer cancel()
+		_, _ = c.Refresh(ctx)
+	}()
+}
+
+// backingOff returns true when a refresh failed too recently for
+// queries to refresh inline again.
+func (c *Cache) backingOff() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.failedAt.IsZero() && time.Since(c.failedAt) < c.revalidateBackoff
+}
+
+// skus returns the current snapshot of cached data.
+func (c *Cache) skus() []SKU {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.data
 }
 
 // Get returns the first matching resource of a given name and type in a location.
 func (c *Cache) Get(ctx context.Context, name, resourceType string) (SKU, bool) {
-	filtered := Filter(c.data, []FilterFn{
-		ResourceTypeFilter(resourceType),
-		NameFilter(name),
-	}...)
-
-	if len(filtered) < 1 {
+	c.revalidate(ctx)
+	sku, found := c.indexed().get(name, resourceType)
+	if !found {
 		return SKU{}, false
 	}
 
-	return filtered[0], true
+	return *sku, true
 }
 
 // List returns all resource types for this location.
 func (c *Cache) List(ctx context.Context, filters ...FilterFn) []SKU {
-	return Filter(c.data, filters...)
+	c.revalidate(ctx)
+	return Filter(c.skus(), filters...)
 }
 
 // GetVirtualMachines returns the list of all virtual machines *SKUs in a given azure location.
 func (c *Cache) GetVirtualMachines(ctx context.Context) []SKU {
-	return Filter(c.data, ResourceTypeFilter(VirtualMachines))
+	return c.ListByResourceType(ctx, VirtualMachines)
 }
 
 // GetVirtualMachineAvailabilityZones returns all virtual machine zones available in a given location.
 func (c *Cache) GetVirtualMachineAvailabilityZones(ctx context.Context) []string {
-	return c.GetAvailabilityZones(ctx, ResourceTypeFilter(VirtualMachines))
+	c.revalidate(ctx)
+	idx := c.indexed()
+	allZones := make(map[string]bool)
+	for _, i := range idx.byType[strings.ToLower(VirtualMachines)] {
+		for zone := range c.skuAvailabilityZones(&idx.data[i]) {
+			allZones[zone] = true
+		}
+	}
+	return zoneList(allZones)
 }
 
 // GetVirtualMachineAvailabilityZonesForSize returns all virtual machine zones available in a given location.
 func (c *Cache) GetVirtualMachineAvailabilityZonesForSize(ctx context.Context, size string) []string {
-	return c.GetAvailabilityZones(ctx, ResourceTypeFilter(VirtualMachines), NameFilter(size))
+	c.revalidate(ctx)
+	allZones := make(map[string]bool)
+	if sku, found := c.indexed().get(size, VirtualMachines); found {
+		for zone := range c.skuAvailabilityZones(sku) {
+			allZones[zone] = true
+		}
+	}
+	return zoneList(allZones)
 }
 
 // GetAvailabilityZones returns the list of all availability zones in a given azure location.
 func (c *Cache) GetAvailabilityZones(ctx context.Context, filters ...FilterFn) []string {
+	c.revalidate(ctx)
+	data := c.skus()
 	allZones := make(map[string]bool)
-
-	Map(c.data, func(s *SKU) SKU {
-		if All(s, filters) {
-			for zone := range s.AvailabilityZones(c.location) {
+	for i := range data {
+		if All(&data[i], filters) {
+			for zone := range c.skuAvailabilityZones(&data[i]) {
 				allZones[zone] = true
 			}
 		}
-		return SKU{}
-	})
+	}
+	return zoneList(allZones)
+}
 
+// zoneList converts a set of zones to a slice.
+func zoneList(allZones map[string]bool) []string {
 	result := make([]string, 0, len(allZones))
 	for zone := range allZones {
 		result = append(result, zone)
@@ -202,11 +470,12 @@ func (c *Cache) Equal(other *Cache) bool {
 		return c.location == other.location &&
 			c.filter == other.filter
 	}
-	if len(c.data) != len(other.data) {
+	data, otherData := c.skus(), other.skus()
+	if len(data) != len(otherData) {
 		return false
 	}
-	for i := range c.data {
-		if c.data[i] != other.data[i] {
+	for i := range data {
+		if data[i] != otherData[i] {
 			return false
 		}
 	}
@@ -2,8 +2,13 @@ package skewer
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -47,6 +52,277 @@ func Test_WithLocation(t *testing.T) {
 	}
 }
 
+func Test_Cache_Refresh(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("foo"),
+			ResourceType: to.StringPtr(VirtualMachines),
+		},
+		{
+			Name:         to.StringPtr("bar"),
+			ResourceType: to.StringPtr(VirtualMachines),
+		},
+	}
+
+	t.Run("should fail without a client", func(t *testing.T) {
+		cache, err := NewStaticCache(Wrap(skus))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := cache.Refresh(context.Background())
+		errClientNil := &ErrClientNil{}
+		if !errors.As(err, &errClientNil) {
+			t.Errorf("expected ErrClientNil, got: %v", err)
+		}
+		if result.Count != len(skus) {
+			t.Errorf("expected static data to be retained with %d skus, got %d", len(skus), result.Count)
+		}
+	})
+
+	t.Run("should replace data on success", func(t *testing.T) {
+		client := &fakeClient{skus: skus[:1]}
+		cache, err := NewCache(context.Background(), WithClient(client))
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.skus = skus
+		result, err := cache.Refresh(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Count != len(skus) || result.Err != nil {
+			t.Errorf("expected %d skus without error, got %d and error '%v'", len(skus), result.Count, result.Err)
+		}
+		if len(cache.List(context.Background())) != len(skus) {
+			t.Errorf("expected cache to contain %d skus after refresh", len(skus))
+		}
+	})
+
+	t.Run("should keep previous data on failure", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(context.Background(), WithClient(client))
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.err = errors.New("boom")
+		result, err := cache.Refresh(context.Background())
+		if err == nil || result.Err != err {
+			t.Fatalf("expected refresh to fail and report error, got '%v' and '%v'", err, result.Err)
+		}
+		if result.Count != len(skus) {
+			t.Errorf("expected %d retained skus, got %d", len(skus), result.Count)
+		}
+		if _, found := cache.Get(context.Background(), "bar", VirtualMachines); !found {
+			t.Errorf("expected previous data to survive failed refresh")
+		}
+	})
+}
+
+// Test_Cache_ConcurrentRefresh is most useful under the race detector:
+// go test -race ./...
+func Test_Cache_ConcurrentRefresh(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceClient, err := newSuccessfulFakeResourceClient(chunk(dataWrapper.Value, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	cache, err := NewCache(ctx, WithClient(&fakeClient{skus: dataWrapper.Value}), WithLocation("eastus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewCache(ctx, WithResourceClient(resourceClient), WithLocation("eastus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 8
+	const iterations = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*iterations)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				if _, err := cache.Refresh(ctx); err != nil {
+					errs <- err
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				if _, found := cache.Get(ctx, "standard_d4s_v3", VirtualMachines); !found {
+					errs <- errors.New("expected to find standard_d4s_v3 during refresh")
+				}
+				if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+					errs <- fmt.Errorf("expected %d virtual machines during refresh, got %d", expectedVirtualMachinesCount, got)
+				}
+				cache.List(ctx, ResourceTypeFilter(Disks))
+				cache.GetAvailabilityZones(ctx)
+				cache.GetVirtualMachineAvailabilityZonesForSize(ctx, "standard_d4s_v3")
+				cache.Equal(other)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func Test_Cache_WithTTL(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("foo"),
+			ResourceType: to.StringPtr(VirtualMachines),
+		},
+	}
+	ctx := context.Background()
+
+	t.Run("should not refresh fresh data", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(ctx, WithClient(client), WithTTL(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache.Get(ctx, "foo", VirtualMachines)
+		cache.List(ctx)
+		cache.GetVirtualMachines(ctx)
+		if calls := client.callCount(); calls != 1 || cache.IsStale() {
+			t.Errorf("expected exactly one call to list skus, got %d", calls)
+		}
+	})
+
+	t.Run("should refresh stale data inline", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(ctx, WithClient(client), WithTTL(time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if !cache.IsStale() {
+			t.Fatalf("expected cache to be stale")
+		}
+		if _, found := cache.Get(ctx, "foo", VirtualMachines); !found {
+			t.Errorf("expected to find sku foo")
+		}
+		if calls := client.callCount(); calls != 2 {
+			t.Errorf("expected stale query to refresh synchronously, got %d calls", calls)
+		}
+	})
+
+	t.Run("should serve stale data when refresh fails", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(ctx, WithClient(client), WithTTL(time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.err = errors.New("boom")
+		time.Sleep(5 * time.Millisecond)
+		if _, found := cache.Get(ctx, "foo", VirtualMachines); !found {
+			t.Errorf("expected to find stale sku foo")
+		}
+	})
+
+	t.Run("should back off inline refreshes after a failure", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(ctx, WithClient(client), WithTTL(50*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.err = errors.New("boom")
+		time.Sleep(60 * time.Millisecond)
+		cache.Get(ctx, "foo", VirtualMachines)
+		cache.List(ctx)
+		cache.GetVirtualMachines(ctx)
+		if calls := client.callCount(); calls != 2 {
+			t.Errorf("expected one failed refresh until the backoff passes, got %d calls", calls-1)
+		}
+
+		client.err = nil
+		time.Sleep(60 * time.Millisecond)
+		cache.List(ctx)
+		if calls := client.callCount(); calls != 3 || cache.IsStale() {
+			t.Errorf("expected a refresh once the backoff passed, got %d calls", calls-1)
+		}
+	})
+
+	t.Run("should back off after a refresh no caller waits for", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(ctx, WithClient(client), WithTTL(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The flight group cancels the refresh once every caller left,
+		// as when the listing is slower than their deadlines.
+		client.err = context.Canceled
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := cache.refresh(cancelled); err == nil {
+			t.Fatal("expected the cancelled refresh to fail")
+		}
+		if !cache.backingOff() {
+			t.Errorf("expected queries to back off after the cancelled refresh")
+		}
+	})
+
+	t.Run("should revalidate in the background", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(ctx, WithClient(client), WithTTL(time.Millisecond), WithStaleWhileRevalidate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if _, found := cache.Get(ctx, "foo", VirtualMachines); !found {
+			t.Errorf("expected to find stale sku foo")
+		}
+		waitForCalls(t, client, 2)
+	})
+
+	t.Run("should time out background revalidation", func(t *testing.T) {
+		client := &contextClient{skus: skus, release: make(chan struct{})}
+		close(client.release)
+		cache, err := NewCache(ctx, WithClient(client), WithTTL(time.Millisecond), WithStaleWhileRevalidate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache.revalidateTimeout = 10 * time.Millisecond
+		client.release = make(chan struct{})
+
+		time.Sleep(5 * time.Millisecond)
+		cache.List(ctx)
+		deadline := time.Now().Add(time.Second)
+		for atomic.LoadInt32(&cache.revalidating) != 0 || atomic.LoadInt32(&client.calls) < 2 {
+			if time.Now().After(deadline) {
+				t.Fatal("expected a hung background revalidation to time out")
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		// Another revalidation may start once the hung one gave up.
+		fetchedAt := cache.FetchedAt()
+		close(client.release)
+		cache.List(ctx)
+		deadline = time.Now().Add(time.Second)
+		for !cache.FetchedAt().After(fetchedAt) {
+			if time.Now().After(deadline) {
+				t.Fatal("expected a new background revalidation to succeed")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
+
 func Test_Cache_List(t *testing.T) {
 	cases := map[string]struct{}{}
 	_ = cases
@@ -0,0 +1,376 @@
+package skewer
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/pkg/errors"
+)
+
+// capability flags describe the parsed form of a capability.
+const (
+	capabilityHasValue uint8 = 1 << iota
+	capabilityIsInt
+	capabilityIsFloat
+	capabilityIsSupported
+)
+
+// parsedCapability holds a capability value parsed into each form the
+// accessors need.
+type parsedCapability struct {
+	value      string
+	flags      uint8
+	intValue   int64
+	floatValue float64
+}
+
+// parseCapability parses a capability value into every form.
+func parseCapability(capability compute.ResourceSkuCapabilities) parsedCapability {
+	parsed := scanCapability(capability)
+	if parsed.flags&capabilityHasValue == 0 {
+		return parsed
+	}
+	if n, err := strconv.ParseInt(parsed.value, 10, 64); err == nil {
+		parsed.intValue = n
+		parsed.flags |= capabilityIsInt
+	}
+	if f, err := strconv.ParseFloat(parsed.value, 64); err == nil {
+		parsed.floatValue = f
+		parsed.flags |= capabilityIsFloat
+	}
+	return parsed
+}
+
+// scanCapability prepares a capability found by scanning, leaving
+// numeric values to be parsed on demand.
+func scanCapability(capability compute.ResourceSkuCapabilities) parsedCapability {
+	var parsed parsedCapability
+	if capability.Value == nil {
+		return parsed
+	}
+	parsed.value = *capability.Value
+	parsed.flags |= capabilityHasValue
+	if strings.EqualFold(parsed.value, string(CapabilitySupported)) {
+		parsed.flags |= capabilityIsSupported
+	}
+	return parsed
+}
+
+// integer returns the value as an integer, or the error from parsing it.
+func (c parsedCapability) integer() (int64, error) {
+	if c.flags&capabilityIsInt != 0 {
+		return c.intValue, nil
+	}
+	return strconv.ParseInt(c.value, 10, 64)
+}
+
+// float returns the value as a float, or the error from parsing it.
+func (c parsedCapability) float() (float64, error) {
+	if c.flags&capabilityIsFloat != 0 {
+		return c.floatValue, nil
+	}
+	return strconv.ParseFloat(c.value, 64)
+}
+
+// maxInlineName is the longest name lookups lower-case without
+// allocating. Capability names are much shorter.
+const maxInlineName = 64
+
+// parsedCapabilityList is a capability list stored with its
+// capabilities parsed once, keyed by lower-cased name. Cached skus refer
+// to its list field, so accessors find the parsed form from the sku
+// alone. The list has room for one element more than its length, a tag
+// which points back to the parsedCapabilityList, so that copies of the
+// list header, and lists appended to or resliced since, are recognized
+// and scanned instead. As with all cached data, the elements of a
+// parsed list must not be modified.
+type parsedCapabilityList struct {
+	list []compute.ResourceSkuCapabilities
+	// byName holds the first capability of each name.
+	byName map[string]parsedCapability
+}
+
+// capabilityListTag is the name of the tag of parsed lists, compared by
+// address.
+var capabilityListTag = "parsedCapabilityList"
+
+// lowerNames shares the lower-cased form of capability names across
+// the lists parsed for one load of data.
+type lowerNames map[string]string
+
+func (n lowerNames) lower(name string) string {
+	if lower, ok := n[name]; ok {
+		return lower
+	}
+	lower := strings.ToLower(name)
+	n[name] = lower
+	return lower
+}
+
+// newParsedCapabilityList copies capabilities into a parsed list and
+// returns a pointer to be stored in a sku. Names are lower-cased with
+// lower, which may share the results.
+func newParsedCapabilityList(capabilities []compute.ResourceSkuCapabilities, lower func(string) string) *[]compute.ResourceSkuCapabilities {
+	parsed := &parsedCapabilityList{
+		list:   make([]compute.ResourceSkuCapabilities, len(capabilities), len(capabilities)+1),
+		byName: make(map[string]parsedCapability, len(capabilities)),
+	}
+	copy(parsed.list, capabilities)
+	for _, capability := range capabilities {
+		if capability.Name == nil {
+			continue
+		}
+		key := lower(*capability.Name)
+		if _, ok := parsed.byName[key]; !ok {
+			parsed.byName[key] = parseCapability(capability)
+		}
+	}
+	parsed.list[:len(capabilities)+1][len(capabilities)] = compute.ResourceSkuCapabilities{
+		Name:  &capabilityListTag,
+		Value: (*string)(unsafe.Pointer(parsed)),
+	}
+	return &parsed.list
+}
+
+// parsedCapabilitiesOf returns the parsed form of a list created by
+// newParsedCapabilityList, or nil for any other list.
+func parsedCapabilitiesOf(list *[]compute.ResourceSkuCapabilities) *parsedCapabilityList {
+	if list == nil || cap(*list) != len(*list)+1 {
+		return nil
+	}
+	tag := (*list)[:len(*list)+1][len(*list)]
+	if tag.Name != &capabilityListTag || unsafe.Pointer(tag.Value) != unsafe.Pointer(list) {
+		return nil
+	}
+	return (*parsedCapabilityList)(unsafe.Pointer(list))
+}
+
+// lookup returns the first capability named name, ignoring case.
+func (p *parsedCapabilityList) lookup(name string) (parsedCapability, bool) {
+	var (
+		buf [maxInlineName]byte
+		key = buf[:0]
+	)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c >= 0x80 || len(key) == maxInlineName {
+			capability, ok := p.byName[strings.ToLower(name)]
+			return capability, ok
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		key = append(key, c)
+	}
+	capability, ok := p.byName[string(key)]
+	return capability, ok
+}
+
+// parseCapabilities returns copies of skus with their capability lists
+// parsed, so the accessors of cached skus need not scan them.
+func parseCapabilities(skus []SKU) []SKU {
+	if skus == nil {
+		return nil
+	}
+	names := make(lowerNames)
+	out := make([]SKU, len(skus))
+	for i := range skus {
+		out[i] = skus[i]
+		if skus[i].Capabilities != nil && len(*skus[i].Capabilities) > 0 {
+			out[i].Capabilities = newParsedCapabilityList(*skus[i].Capabilities, names.lower)
+		}
+	}
+	return out
+}
+
+// Capabilities is a read-only view of the capabilities of a cached sku,
+// for filters over many of them. Names are matched ignoring case, as by
+// the accessors of SKU.
+type Capabilities struct {
+	list *[]compute.ResourceSkuCapabilities
+}
+
+// CapabilityFilterFn is a filter over the parsed capabilities of a sku.
+type CapabilityFilterFn func(Capabilities) bool
+
+// GetIntegerQuantity is SKU.GetCapabilityIntegerQuantity over parsed
+// capabilities.
+func (c Capabilities) GetIntegerQuantity(name string) (int64, error) {
+	capability, ok := lookupCapability(c.list, name)
+	return capabilityInteger(capability, ok, name)
+}
+
+// GetFloatQuantity is SKU.GetCapabilityFloatQuantity over parsed
+// capabilities.
+func (c Capabilities) GetFloatQuantity(name string) (float64, error) {
+	capability, ok := lookupCapability(c.list, name)
+	return capabilityFloat(capability, ok, name)
+}
+
+// Has is SKU.HasCapability over parsed capabilities.
+func (c Capabilities) Has(name string) bool {
+	capability, ok := lookupCapability(c.list, name)
+	return ok && capability.flags&capabilityIsSupported != 0
+}
+
+// HasWithSeparator is SKU.HasCapabilityWithSeparator over parsed
+// capabilities.
+func (c Capabilities) HasWithSeparator(name, value string) bool {
+	capability, ok := lookupCapability(c.list, name)
+	return capabilityContains(capability, ok, value)
+}
+
+// HasWithCapacity is SKU.HasCapabilityWithCapacity over parsed
+// capabilities.
+func (c Capabilities) HasWithCapacity(name string, value int64) (bool, error) {
+	capability, ok := lookupCapability(c.list, name)
+	return capabilityAtLeast(capability, ok, value)
+}
+
+// capabilityInteger returns the value of a capability found by a lookup
+// as an integer, or the error describing why it could not.
+func capabilityInteger(capability parsedCapability, ok bool, name string) (int64, error) {
+	if !ok {
+		return -1, &ErrCapabilityNotFound{name}
+	}
+	if capability.flags&capabilityHasValue == 0 {
+		return -1, &ErrCapabilityValueNil{name}
+	}
+	intVal, err := capability.integer()
+	if err != nil {
+		return -1, &ErrCapabilityValueParse{name, capability.value, err}
+	}
+	return intVal, nil
+}
+
+// capabilityFloat returns the value of a capability found by a lookup
+// as a float, or the error describing why it could not.
+func capabilityFloat(capability parsedCapability, ok bool, name string) (float64, error) {
+	if !ok {
+		return -1, &ErrCapabilityNotFound{name}
+	}
+	if capability.flags&capabilityHasValue == 0 {
+		return -1, &ErrCapabilityValueNil{name}
+	}
+	floatVal, err := capability.float()
+	if err != nil {
+		return -1, &ErrCapabilityValueParse{name, capability.value, err}
+	}
+	return floatVal, nil
+}
+
+// capabilityContains returns true when a capability found by a lookup
+// has a value containing value.
+func capabilityContains(capability parsedCapability, ok bool, value string) bool {
+	return ok && capability.flags&capabilityHasValue != 0 && strings.Contains(capability.value, value)
+}
+
+// capabilityAtLeast returns true when a capability found by a lookup has
+// an integer value of at least value.
+func capabilityAtLeast(capability parsedCapability, ok bool, value int64) (bool, error) {
+	if !ok || capability.flags&capabilityHasValue == 0 {
+		return false, nil
+	}
+	intVal, err := capability.integer()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse string '%s' as int64", capability.value)
+	}
+	return intVal >= value, nil
+}
+
+// lookupCapability returns the first capability in list named name,
+// ignoring case. Parsed lists are looked up by name, and other lists are
+// scanned as they are now.
+func lookupCapability(list *[]compute.ResourceSkuCapabilities, name string) (parsedCapability, bool) {
+	if list == nil {
+		return parsedCapability{}, false
+	}
+	if parsed := parsedCapabilitiesOf(list); parsed != nil {
+		return parsed.lookup(name)
+	}
+	for _, capability := range *list {
+		if capability.Name != nil && strings.EqualFold(*capability.Name, name) {
+			return scanCapability(capability), true
+		}
+	}
+	return parsedCapability{}, false
+}
+
+// capabilityReader reads typed values from a capability list, recording
+// the first error so views can read many values before checking.
+type capabilityReader struct {
+	list *[]compute.ResourceSkuCapabilities
+	err  error
+}
+
+// lookup returns a capability with a value. It records an error when
+// the capability is required and not found, or when its value is nil.
+func (r *capabilityReader) lookup(name string, required bool) (parsedCapability, bool) {
+	capability, ok := lookupCapability(r.list, name)
+	switch {
+	case !ok:
+		if required {
+			r.fail(&ErrCapabilityNotFound{name})
+		}
+		return parsedCapability{}, false
+	case capability.flags&capabilityHasValue == 0:
+		r.fail(&ErrCapabilityValueNil{name})
+		return parsedCapability{}, false
+	}
+	return capability, true
+}
+
+// integer returns the value of an integer capability, or zero when an
+// optional capability is not found.
+func (r *capabilityReader) integer(name string, required bool) int64 {
+	capability, ok := r.lookup(name, required)
+	if !ok {
+		return 0
+	}
+	n, err := capability.integer()
+	if err != nil {
+		r.fail(&ErrCapabilityValueParse{name, capability.value, err})
+		return 0
+	}
+	return n
+}
+
+// float returns the value of a floating point capability, or zero when
+// an optional capability is not found.
+func (r *capabilityReader) float(name string, required bool) float64 {
+	capability, ok := r.lookup(name, required)
+	if !ok {
+		return 0
+	}
+	f, err := capability.float()
+	if err != nil {
+		r.fail(&ErrCapabilityValueParse{name, capability.value, err})
+		return 0
+	}
+	return f
+}
+
+// supported returns true when a capability is supported, as
+// HasCapability does.
+func (r *capabilityReader) supported(name string) bool {
+	capability, ok := lookupCapability(r.list, name)
+	return ok && capability.flags&capabilityIsSupported != 0
+}
+
+// values returns the values of a comma separated capability, or nil
+// when it is not found.
+func (r *capabilityReader) values(name string) []string {
+	capability, ok := r.lookup(name, false)
+	if !ok || capability.value == "" {
+		return nil
+	}
+	return strings.Split(capability.value, ",")
+}
+
+func (r *capabilityReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
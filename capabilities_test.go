@@ -0,0 +1,457 @@
+package skewer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+// capabilityResults collects the result of every capability accessor.
+type capabilityResults struct {
+	Integer          int64
+	IntegerErr       string
+	Float            float64
+	FloatErr         string
+	Supported        bool
+	Zonal            bool
+	Separator        bool
+	Capacity         bool
+	CapacityErr      string
+	VCPU             int64
+	EncryptionAtHost bool
+}
+
+func capabilityResultsOf(sku *SKU, name string) capabilityResults {
+	var results capabilityResults
+	var err error
+	if results.Integer, err = sku.GetCapabilityIntegerQuantity(name); err != nil {
+		results.IntegerErr = err.Error()
+	}
+	if results.Float, err = sku.GetCapabilityFloatQuantity(name); err != nil {
+		results.FloatErr = err.Error()
+	}
+	results.Supported = sku.HasCapability(name)
+	results.Zonal = sku.HasZonalCapability(name)
+	results.Separator = sku.HasCapabilityWithSeparator(name, "V2")
+	if results.Capacity, err = sku.HasCapabilityWithCapacity(name, 4); err != nil {
+		results.CapacityErr = err.Error()
+	}
+	results.VCPU, _ = sku.VCPU()
+	results.EncryptionAtHost = sku.IsEncryptionAtHostSupported()
+	return results
+}
+
+func parsedCapabilityResultsOf(capabilities Capabilities, name string) capabilityResults {
+	var results capabilityResults
+	var err error
+	if results.Integer, err = capabilities.GetIntegerQuantity(name); err != nil {
+		results.IntegerErr = err.Error()
+	}
+	if results.Float, err = capabilities.GetFloatQuantity(name); err != nil {
+		results.FloatErr = err.Error()
+	}
+	results.Supported = capabilities.Has(name)
+	results.Separator = capabilities.HasWithSeparator(name, "V2")
+	if results.Capacity, err = capabilities.HasWithCapacity(name, 4); err != nil {
+		results.CapacityErr = err.Error()
+	}
+	results.VCPU, _ = capabilities.GetIntegerQuantity(VCPUs)
+	results.EncryptionAtHost = capabilities.Has(EncryptionAtHost)
+	return results
+}
+
+func Test_capabilityAccessors(t *testing.T) {
+	sku := compute.ResourceSku{
+		ResourceType: to.StringPtr(VirtualMachines),
+		Name:         to.StringPtr("Standard_D4s_v3"),
+		Capabilities: &[]compute.ResourceSkuCapabilities{
+			{Name: to.StringPtr("VCPUS"), Value: to.StringPtr("4")},
+			{Name: to.StringPtr("vCPUs"), Value: to.StringPtr("8")},
+			{Name: to.StringPtr("MemoryGB"), Value: to.StringPtr("16.5")},
+			{Name: to.StringPtr("encryptionathostsupported"), Value: to.StringPtr("true")},
+			{Name: to.StringPtr("HyperVGenerations"), Value: to.StringPtr("V1,V2")},
+			{Name: to.StringPtr("Nil")},
+			{Value: to.StringPtr("nameless")},
+		},
+		LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			{
+				Location: to.StringPtr("eastus"),
+				ZoneDetails: &[]compute.ResourceSkuZoneDetails{
+					{Capabilities: &[]compute.ResourceSkuCapabilities{
+						{Name: to.StringPtr("UltraSSDAvailable"), Value: to.StringPtr("True")},
+					}},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		name   string
+		expect capabilityResults
+	}{
+		"should match integer capability ignoring case, using the first match": {
+			name: "vcpus",
+			expect: capabilityResults{
+				Integer:          4,
+				Float:            4,
+				Capacity:         true,
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+		"should match float capability ignoring case": {
+			name: "MEMORYGB",
+			expect: capabilityResults{
+				Integer:          -1,
+				IntegerErr:       "MEMORYGBCapabilityValueParse: failed to parse string '16.5' as int64, error: 'strconv.ParseInt: parsing \"16.5\": invalid syntax'", // nolint:lll
+				Float:            16.5,
+				CapacityErr:      "failed to parse string '16.5' as int64: strconv.ParseInt: parsing \"16.5\": invalid syntax",
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+		"should match supported capability ignoring case of name and value": {
+			name: EncryptionAtHost,
+			expect: capabilityResults{
+				Integer:          -1,
+				IntegerErr:       "EncryptionAtHostSupportedCapabilityValueParse: failed to parse string 'true' as int64, error: 'strconv.ParseInt: parsing \"true\": invalid syntax'", // nolint:lll
+				Float:            -1,
+				FloatErr:         "EncryptionAtHostSupportedCapabilityValueParse: failed to parse string 'true' as int64, error: 'strconv.ParseFloat: parsing \"true\": invalid syntax'", // nolint:lll
+				Supported:        true,
+				CapacityErr:      "failed to parse string 'true' as int64: strconv.ParseInt: parsing \"true\": invalid syntax",
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+		"should match separated capability ignoring case": {
+			name: "hypervgenerations",
+			expect: capabilityResults{
+				Integer:          -1,
+				IntegerErr:       "hypervgenerationsCapabilityValueParse: failed to parse string 'V1,V2' as int64, error: 'strconv.ParseInt: parsing \"V1,V2\": invalid syntax'", // nolint:lll
+				Float:            -1,
+				FloatErr:         "hypervgenerationsCapabilityValueParse: failed to parse string 'V1,V2' as int64, error: 'strconv.ParseFloat: parsing \"V1,V2\": invalid syntax'", // nolint:lll
+				Separator:        true,
+				CapacityErr:      "failed to parse string 'V1,V2' as int64: strconv.ParseInt: parsing \"V1,V2\": invalid syntax",
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+		"should match zonal capability ignoring case": {
+			name: "ultrassdavailable",
+			expect: capabilityResults{
+				Integer:          -1,
+				IntegerErr:       "ultrassdavailableCapabilityNotFound",
+				Float:            -1,
+				FloatErr:         "ultrassdavailableCapabilityNotFound",
+				Zonal:            true,
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+		"should report nil value": {
+			name: "nil",
+			expect: capabilityResults{
+				Integer:          -1,
+				IntegerErr:       "nilCapabilityValueNil",
+				Float:            -1,
+				FloatErr:         "nilCapabilityValueNil",
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+		"should not match nameless capability": {
+			name: "",
+			expect: capabilityResults{
+				Integer:          -1,
+				IntegerErr:       "CapabilityNotFound",
+				Float:            -1,
+				FloatErr:         "CapabilityNotFound",
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+		"should not match unknown long names": {
+			name: strings.Repeat("x", maxInlineName+1),
+			expect: capabilityResults{
+				Integer:          -1,
+				IntegerErr:       strings.Repeat("x", maxInlineName+1) + "CapabilityNotFound",
+				Float:            -1,
+				FloatErr:         strings.Repeat("x", maxInlineName+1) + "CapabilityNotFound",
+				VCPU:             4,
+				EncryptionAtHost: true,
+			},
+		},
+	}
+
+	caches := map[string][]CacheOption{
+		"plain":   nil,
+		"compact": {WithCompactStorage()},
+	}
+	cached := make(map[string]SKU)
+	for storage, opts := range caches {
+		cache, err := NewStaticCache([]SKU{SKU(sku)}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cached[storage] = cache.List(context.Background())[0]
+		if parsedCapabilitiesOf(cached[storage].Capabilities) == nil {
+			t.Fatalf("expected %s cache to parse capabilities", storage)
+		}
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			scanned := SKU(sku)
+			if diff := cmp.Diff(tc.expect, capabilityResultsOf(&scanned, tc.name)); diff != "" {
+				t.Errorf("expected scanned results to match: %s", diff)
+			}
+			for storage := range caches {
+				parsed := cached[storage]
+				if diff := cmp.Diff(tc.expect, capabilityResultsOf(&parsed, tc.name)); diff != "" {
+					t.Errorf("expected %s cached results to match: %s", storage, diff)
+				}
+				// Capabilities do not include zone details.
+				expect := tc.expect
+				expect.Zonal = false
+				if diff := cmp.Diff(expect, parsedCapabilityResultsOf(Capabilities{parsed.Capabilities}, tc.name)); diff != "" {
+					t.Errorf("expected %s parsed results to match: %s", storage, diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_parsedCapabilityList(t *testing.T) {
+	list := newParsedCapabilityList([]compute.ResourceSkuCapabilities{
+		{Name: to.StringPtr("Größe"), Value: to.StringPtr("1")},
+		{Name: to.StringPtr(strings.Repeat("Long", maxInlineName)), Value: to.StringPtr("2")},
+	}, strings.ToLower)
+	parsed := parsedCapabilitiesOf(list)
+	if parsed == nil {
+		t.Fatal("expected list to be parsed")
+	}
+
+	cases := map[string]struct {
+		name   string
+		expect int64
+		found  bool
+	}{
+		"should not fold non-ascii letters beyond simple case": {
+			name:   "GRÖSSE",
+			found:  false,
+			expect: 0,
+		},
+		"should match non-ascii names with different ascii case": {
+			name:   "grÖße",
+			found:  true,
+			expect: 1,
+		},
+		"should match names too long to lower-case inline": {
+			name:   strings.Repeat("LONG", maxInlineName),
+			found:  true,
+			expect: 2,
+		},
+		"should not match prefixes of long names": {
+			name: strings.Repeat("LONG", maxInlineName/4),
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			capability, found := parsed.lookup(tc.name)
+			if found != tc.found {
+				t.Fatalf("expected found to be %t", tc.found)
+			}
+			if diff := cmp.Diff(tc.expect, capability.intValue); diff != "" {
+				t.Errorf("expected value to match: %s", diff)
+			}
+		})
+	}
+
+	t.Run("should not use the parsed form of copies or changed lists", func(t *testing.T) {
+		copied := *list
+		appended := append(*list, compute.ResourceSkuCapabilities{Name: to.StringPtr("vCPUs"), Value: to.StringPtr("4")})
+		resliced := (*list)[:1]
+		for name, other := range map[string]*[]compute.ResourceSkuCapabilities{
+			"copy":     &copied,
+			"append":   &appended,
+			"reslice":  &resliced,
+			"original": list,
+		} {
+			if parsedCapabilitiesOf(other) != nil {
+				t.Errorf("expected %s not to be parsed", name)
+			}
+		}
+	})
+}
+
+func Test_SKU_capabilitiesFollowChanges(t *testing.T) {
+	capabilities := []compute.ResourceSkuCapabilities{{Name: to.StringPtr(VCPUs), Value: to.StringPtr("2")}}
+	data := []SKU{{Capabilities: &capabilities}}
+	if _, err := NewStaticCache(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// Skus are plain values, so accessors must reflect changes made to
+	// them, even when the same data was given to a cache.
+	capabilities[0].Value = to.StringPtr("8")
+	capabilities = append(capabilities, compute.ResourceSkuCapabilities{Name: to.StringPtr(MemoryGB), Value: to.StringPtr("16")})
+	data[0].Capabilities = &capabilities
+
+	vcpus, err := data[0].VCPU()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(int64(8), vcpus); diff != "" {
+		t.Errorf("expected changed vCPUs: %s", diff)
+	}
+	memory, err := data[0].Memory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(float64(16), memory); diff != "" {
+		t.Errorf("expected appended memory: %s", diff)
+	}
+}
+
+func Test_Cache_ListByCapabilities(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := NewStaticCache(Wrap(dataWrapper.Value))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		resourceType string
+		filters      []CapabilityFilterFn
+		expect       func(*SKU) bool
+	}{
+		"should match all skus of a resource type without filters": {
+			resourceType: VirtualMachines,
+			expect:       ResourceTypeFilter(VirtualMachines),
+		},
+		"should match resource type ignoring case": {
+			resourceType: "DISKS",
+			filters: []CapabilityFilterFn{func(c Capabilities) bool {
+				ok, err := c.HasWithCapacity(MaxSizeGiB, 16384)
+				return err == nil && ok
+			}},
+			expect: DiskSizeAtLeast(16384),
+		},
+		"should match the same skus as scanning": {
+			resourceType: VirtualMachines,
+			filters:      []CapabilityFilterFn{filterParsedCapabilities},
+			expect: func(s *SKU) bool {
+				return s.IsResourceType(VirtualMachines) && len(filterCapabilities([]SKU{*s})) == 1
+			},
+		},
+		"should match nothing for unknown resource types": {
+			resourceType: "unknown",
+			expect:       func(*SKU) bool { return false },
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			expect := Filter(cache.List(context.Background()), tc.expect)
+			got := cache.ListByCapabilities(context.Background(), tc.resourceType, tc.filters...)
+			if diff := cmp.Diff(len(expect), len(got)); diff != "" {
+				t.Fatalf("expected matching skus: %s", diff)
+			}
+			if diff := cmp.Diff(expect, got); diff != "" {
+				t.Errorf("expected matching skus: %s", diff)
+			}
+		})
+	}
+}
+
+// filterCapabilities is a filter-heavy workload touching several
+// capabilities of every sku, most listed towards the end.
+func filterCapabilities(data []SKU) []SKU {
+	return Filter(data, func(s *SKU) bool {
+		if !s.HasCapability(AcceleratedNetworking) || !s.HasCapabilityWithSeparator(HyperVGenerations, "V2") {
+			return false
+		}
+		ok, err := s.HasCapabilityWithCapacity("MaxDataDiskCount", 8)
+		if err != nil || !ok {
+			return false
+		}
+		vcpus, err := s.VCPU()
+		if err != nil || vcpus < 4 {
+			return false
+		}
+		memory, err := s.Memory()
+		return err == nil && memory >= 16
+	})
+}
+
+// BenchmarkCapabilities_Scan measures capability accessors of skus which
+// were not cached, and so are scanned, as a baseline for
+// BenchmarkCapabilities_Cached and BenchmarkCapabilities_Parsed.
+func BenchmarkCapabilities_Scan(b *testing.B) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := Wrap(dataWrapper.Value)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if len(filterCapabilities(data)) < 1 {
+			b.Fatal("expected to find skus")
+		}
+	}
+}
+
+// BenchmarkCapabilities_Cached measures capability accessors of cached
+// skus, which look up capabilities parsed when the cache was loaded.
+func BenchmarkCapabilities_Cached(b *testing.B) {
+	data := newBenchmarkCache(b).List(context.Background())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if len(filterCapabilities(data)) < 1 {
+			b.Fatal("expected to find skus")
+		}
+	}
+}
+
+// filterParsedCapabilities is filterCapabilities over parsed
+// capabilities.
+func filterParsedCapabilities(c Capabilities) bool {
+	if !c.Has(AcceleratedNetworking) || !c.HasWithSeparator(HyperVGenerations, "V2") {
+		return false
+	}
+	ok, err := c.HasWithCapacity("MaxDataDiskCount", 8)
+	if err != nil || !ok {
+		return false
+	}
+	vcpus, err := c.GetIntegerQuantity(VCPUs)
+	if err != nil || vcpus < 4 {
+		return false
+	}
+	memory, err := c.GetFloatQuantity(MemoryGB)
+	return err == nil && memory >= 16
+}
+
+func BenchmarkCapabilities_Parsed(b *testing.B) {
+	cache := newBenchmarkCache(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if len(cache.ListByCapabilities(ctx, VirtualMachines, filterParsedCapabilities)) < 1 {
+			b.Fatal("expected to find skus")
+		}
+	}
+}
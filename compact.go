@@ -0,0 +1,478 @@
+package skewer
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+// WithCompactStorage is a functional option to store skus interned.
+// Skus keep the layout of compute.ResourceSku, but strings and lists
+// which repeat across skus and refreshes, such as capability names and
+// values, zone lists and whole capability sets, are stored once and
+// shared. Zone lists of plain zone numbers are also recorded as bitsets,
+// which the zone queries of the cache use. The caches of a
+// MultiLocationCache created with this option share one interner, so
+// values are also shared across locations, which considerably reduces
+// the memory they hold.
+//
+// Skus returned by a compact cache are equal to those returned by the
+// Azure client, but share the memory their pointers refer to with other
+// skus of the cache, or of its MultiLocationCache. As with any cache,
+// they must not be modified. Interned values are counted by the data
+// referring to them, and released once no data held by any cache
+// sharing the interner refers to them.
+func WithCompactStorage() CacheOption {
+	return func(c *Cache) error {
+		c.interner = newInterner()
+		return nil
+	}
+}
+
+// zoneSet is a bitset of numeric availability zones, bit n representing
+// zone "n".
+type zoneSet uint64
+
+// maxZone is the largest zone number a zoneSet can represent.
+const maxZone = 63
+
+// newZoneSet converts a list of zones to a bitset. It returns false if a
+// zone is not a number a zoneSet can represent, or is not in canonical
+// form, so the set could not be converted back to the same strings.
+func newZoneSet(zones []string) (zoneSet, bool) {
+	var set zoneSet
+	for _, zone := range zones {
+		n, err := strconv.Atoi(zone)
+		if err != nil || n < 0 || n > maxZone || strconv.Itoa(n) != zone {
+			return 0, false
+		}
+		set |= 1 << uint(n)
+	}
+	return set, true
+}
+
+// toMap converts the set to the representation used by AvailabilityZones.
+func (z zoneSet) toMap() map[string]bool {
+	zones := make(map[string]bool)
+	for n := 0; n <= maxZone; n++ {
+		if z&(1<<uint(n)) != 0 {
+			zones[strconv.Itoa(n)] = true
+		}
+	}
+	return zones
+}
+
+// interner deduplicates sku data. Lists are keyed by content, including
+// whether they or their elements are nil, so interned skus are equal to
+// the originals. Entries are counted each time interned data refers to
+// them, and dropped once all data referring to them has been released,
+// so an interner only holds the values of data still in use.
+type interner struct {
+	mu           sync.RWMutex
+	strings      map[string]*internEntry
+	stringLists  map[string]*internEntry
+	capabilities map[string]*internEntry
+	locationInfo map[string]*internEntry
+	restrictions map[string]*internEntry
+
+	// zoneSets holds the bitsets of interned zone lists, keyed by the
+	// shared pointer.
+	zoneSets map[*[]string]zoneSet
+}
+
+// internEntry is an interned value, counted by the data and entries
+// referring to it.
+type internEntry struct {
+	table map[string]*internEntry
+	key   string
+	refs  int
+	value interface{}
+	// children holds the entries value refers to, released with it.
+	children []*internEntry
+}
+
+func newInterner() *interner {
+	return &interner{
+		strings:      make(map[string]*internEntry),
+		stringLists:  make(map[string]*internEntry),
+		capabilities: make(map[string]*internEntry),
+		locationInfo: make(map[string]*internEntry),
+		restrictions: make(map[string]*internEntry),
+		zoneSets:     make(map[*[]string]zoneSet),
+	}
+}
+
+// compact returns copies of skus referring to interned data, and the
+// entries they hold, to be released once the copies are no longer
+// cached. The lock is taken for one sku at a time, so caches sharing an
+// interner are not blocked for a whole refresh.
+func (in *interner) compact(skus []SKU) ([]SKU, []*internEntry) {
+	if skus == nil {
+		return nil, nil
+	}
+
+	out := make([]SKU, len(skus))
+	var held []*internEntry
+	for i := range skus {
+		in.mu.Lock()
+		sku := skus[i]
+		sku.ResourceType = in.string(sku.ResourceType, &held)
+		sku.Name = in.string(sku.Name, &held)
+		sku.Tier = in.string(sku.Tier, &held)
+		sku.Size = in.string(sku.Size, &held)
+		sku.Family = in.string(sku.Family, &held)
+		sku.Kind = in.string(sku.Kind, &held)
+		sku.Locations = in.stringList(sku.Locations, &held)
+		sku.LocationInfo = in.locationInfoList(sku.LocationInfo, &held)
+		sku.APIVersions = in.stringList(sku.APIVersions, &held)
+		sku.Capabilities = in.capabilityList(sku.Capabilities, &held)
+		sku.Restrictions = in.restrictionList(sku.Restrictions, &held)
+		in.mu.Unlock()
+		out[i] = sku
+	}
+	return out, held
+}
+
+// release drops entries held by data which is no longer cached.
+func (in *interner) release(held []*internEntry) {
+	if len(held) == 0 {
+		return
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.releaseLocked(held)
+}
+
+func (in *interner) releaseLocked(held []*internEntry) {
+	for _, entry := range held {
+		entry.refs--
+		if entry.refs > 0 {
+			continue
+		}
+		delete(entry.table, entry.key)
+		if list, ok := entry.value.(*[]string); ok {
+			delete(in.zoneSets, list)
+		}
+		in.releaseLocked(entry.children)
+	}
+}
+
+// acquire returns the entry for key in table, creating its value if it
+// is new, and records it as held.
+func (in *interner) acquire(table map[string]*internEntry, key string, held *[]*internEntry,
+	create func(children *[]*internEntry) interface{}) interface{} {
+	entry, ok := table[key]
+	if !ok {
+		entry = &internEntry{table: table, key: key}
+		entry.value = create(&entry.children)
+		table[key] = entry
+	}
+	entry.refs++
+	*held = append(*held, entry)
+	return entry.value
+}
+
+// zoneSet returns the bitset of an interned zone list.
+func (in *interner) zoneSet(zones *[]string) (zoneSet, bool) {
+	if zones == nil {
+		return 0, false
+	}
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	set, ok := in.zoneSets[zones]
+	return set, ok
+}
+
+func (in *interner) string(s *string, held *[]*internEntry) *string {
+	if s == nil {
+		return nil
+	}
+	return in.acquire(in.strings, *s, held, func(*[]*internEntry) interface{} {
+		interned := new(string)
+		*interned = *s
+		return interned
+	}).(*string)
+}
+
+func (in *interner) stringList(list *[]string, held *[]*internEntry) *[]string {
+	if list == nil {
+		return nil
+	}
+	return in.acquire(in.stringLists, stringListKey(*list), held, func(children *[]*internEntry) interface{} {
+		var values []string
+		if *list != nil {
+			values = make([]string, len(*list))
+			for i := range *list {
+				values[i] = *in.string(&(*list)[i], children)
+			}
+		}
+		interned := &values
+		if set, ok := newZoneSet(values); ok {
+			in.zoneSets[interned] = set
+		}
+		return interned
+	}).(*[]string)
+}
+
+func (in *interner) capabilityList(list *[]compute.ResourceSkuCapabilities, held *[]*internEntry) *[]compute.ResourceSkuCapabilities {
+	if list == nil {
+		return nil
+	}
+	var key strings.Builder
+	writeCapabilitiesKey(&key, *list)
+	return in.acquire(in.capabilities, key.String(), held, func(children *[]*internEntry) interface{} {
+		if len(*list) == 0 {
+			values := *list
+			return &values
+		}
+		values := make([]compute.ResourceSkuCapabilities, len(*list))
+		for i, capability := range *list {
+			values[i] = compute.ResourceSkuCapabilities{
+				Name:  in.string(capability.Name, children),
+				Value: in.string(capability.Value, children),
+			}
+		}
+		return newParsedCapabilityList(values, func(name string) string {
+			lower := strings.ToLower(name)
+			return *in.string(&lower, children)
+		})
+	}).(*[]compute.ResourceSkuCapabilities)
+}
+
+func (in *interner) locationInfoList(list *[]compute.ResourceSkuLocationInfo, held *[]*internEntry) *[]compute.ResourceSkuLocationInfo {
+	if list == nil {
+		return nil
+	}
+	var key strings.Builder
+	writeLocationInfoKey(&key, *list)
+	return in.acquire(in.locationInfo, key.String(), held, func(children *[]*internEntry) interface{} {
+		var values []compute.ResourceSkuLocationInfo
+		if *list != nil {
+			values = make([]compute.ResourceSkuLocationInfo, len(*list))
+			for i, info := range *list {
+				values[i] = compute.ResourceSkuLocationInfo{
+					Location: in.string(info.Location, children),
+					Zones:    in.stringList(info.Zones, children),
+				}
+				if info.ZoneDetails == nil {
+					continue
+				}
+				details := make([]compute.ResourceSkuZoneDetails, len(*info.ZoneDetails))
+				if *info.ZoneDetails == nil {
+					details = nil
+				}
+				for j, detail := range *info.ZoneDetails {
+					details[j] = compute.ResourceSkuZoneDetails{
+						Name:         in.stringList(detail.Name, children),
+						Capabilities: in.capabilityList(detail.Capabilities, children),
+					}
+				}
+				values[i].ZoneDetails = &details
+			}
+		}
+		return &values
+	}).(*[]compute.ResourceSkuLocationInfo)
+}
+
+func (in *interner) restrictionList(list *[]compute.ResourceSkuRestrictions, held *[]*internEntry) *[]compute.ResourceSkuRestrictions {
+	if list == nil {
+		return nil
+	}
+	var key strings.Builder
+	writeRestrictionsKey(&key, *list)
+	return in.acquire(in.restrictions, key.String(), held, func(children *[]*internEntry) interface{} {
+		var values []compute.ResourceSkuRestrictions
+		if *list != nil {
+			values = make([]compute.ResourceSkuRestrictions, len(*list))
+			for i, restriction := range *list {
+				values[i] = compute.ResourceSkuRestrictions{
+					Type:       restriction.Type,
+					Values:     in.stringList(restriction.Values, children),
+					ReasonCode: restriction.ReasonCode,
+				}
+				if restriction.RestrictionInfo != nil {
+					values[i].RestrictionInfo = &compute.ResourceSkuRestrictionInfo{
+						Locations: in.stringList(restriction.RestrictionInfo.Locations, children),
+						Zones:     in.stringList(restriction.RestrictionInfo.Zones, children),
+					}
+				}
+			}
+		}
+		return &values
+	}).(*[]compute.ResourceSkuRestrictions)
+}
+
+// The key writers encode values unambiguously: strings are length
+// prefixed, and nil is distinguished from empty.
+
+func writeStringKey(b *strings.Builder, s *string) {
+	if s == nil {
+		b.WriteString("~")
+		return
+	}
+	b.WriteString(strconv.Itoa(len(*s)))
+	b.WriteByte(':')
+	b.WriteString(*s)
+}
+
+func writeStringListKey(b *strings.Builder, list *[]string) {
+	if list == nil {
+		b.WriteString("~")
+		return
+	}
+	b.WriteString(stringListKey(*list))
+}
+
+func stringListKey(list []string) string {
+	if list == nil {
+		return "-"
+	}
+	var b strings.Builder
+	b.WriteString("[")
+	for i := range list {
+		writeStringKey(&b, &list[i])
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func writeCapabilitiesKey(b *strings.Builder, list []compute.ResourceSkuCapabilities) {
+	if list == nil {
+		b.WriteString("-")
+		return
+	}
+	b.WriteString("[")
+	for _, capability := range list {
+		writeStringKey(b, capability.Name)
+		writeStringKey(b, capability.Value)
+	}
+	b.WriteString("]")
+}
+
+func writeLocationInfoKey(b *strings.Builder, list []compute.ResourceSkuLocationInfo) {
+	if list == nil {
+		b.WriteString("-")
+		return
+	}
+	b.WriteString("[")
+	for _, info := range list {
+		writeStringKey(b, info.Location)
+		writeStringListKey(b, info.Zones)
+		if info.ZoneDetails == nil {
+			b.WriteString("~")
+			continue
+		}
+		if *info.ZoneDetails == nil {
+			b.WriteString("-")
+			continue
+		}
+		b.WriteString("[")
+		for _, detail := range *info.ZoneDetails {
+			writeStringListKey(b, detail.Name)
+			if detail.Capabilities == nil {
+				b.WriteString("~")
+			} else {
+				writeCapabilitiesKey(b, *detail.Capabilities)
+			}
+		}
+		b.WriteString("]")
+	}
+	b.WriteString("]")
+}
+
+func writeRestrictionsKey(b *strings.Builder, list []compute.ResourceSkuRestrictions) {
+	if list == nil {
+		b.WriteString("-")
+		return
+	}
+	b.WriteString("[")
+	for _, restriction := range list {
+		restrictionType, reasonCode := string(restriction.Type), string(restriction.ReasonCode)
+		writeStringKey(b, &restrictionType)
+		writeStringKey(b, &reasonCode)
+		writeStringListKey(b, restriction.Values)
+		if restriction.RestrictionInfo == nil {
+			b.WriteString("~")
+			continue
+		}
+		writeStringListKey(b, restriction.RestrictionInfo.Locations)
+		writeStringListKey(b, restriction.RestrictionInfo.Zones)
+	}
+	b.WriteString("]")
+}
+
+// prepareData prepares data to be cached: it interns data if the cache
+// uses compact storage, returning the entries it holds, and parses the
+// capabilities of every sku.
+func (c *Cache) prepareData(data []SKU) ([]SKU, []*internEntry) {
+	if c.interner == nil {
+		return parseCapabilities(data), nil
+	}
+	return c.interner.compact(data)
+}
+
+// replaceData replaces cached data with data prepared by prepareData,
+// releasing the entries held by the data it replaces. It must be called
+// with c.mu held, unless the cache has not yet been returned to callers
+// and so cannot be accessed concurrently.
+func (c *Cache) replaceData(data []SKU, held []*internEntry) {
+	replaced := c.held
+	c.data = data
+	c.index = newSKUIndex(data)
+	c.held = held
+	if c.interner != nil {
+		c.interner.release(replaced)
+	}
+}
+
+// skuAvailabilityZones is AvailabilityZones in the location of the
+// cache, using the zone bitsets of compact data when possible.
+func (c *Cache) skuAvailabilityZones(s *SKU) map[string]bool {
+	if c.interner != nil {
+		if zones, ok := c.interner.availabilityZones(s, c.location); ok {
+			return zones
+		}
+	}
+	return s.AvailabilityZones(c.location)
+}
+
+// availabilityZones implements AvailabilityZones over zone bitsets. It
+// returns false when any zone list involved was not interned.
+func (in *interner) availabilityZones(s *SKU, location string) (map[string]bool, bool) {
+	if s.LocationInfo == nil {
+		return nil, false
+	}
+	for _, info := range *s.LocationInfo {
+		if info.Location == nil {
+			return nil, false
+		}
+		if !strings.EqualFold(*info.Location, location) {
+			continue
+		}
+
+		available, ok := in.zoneSet(info.Zones)
+		if !ok {
+			return nil, false
+		}
+		if s.Restrictions != nil {
+			for _, restriction := range *s.Restrictions {
+				// Can't deploy to any zones in this location.
+				if restriction.Type == compute.Location {
+					return nil, true
+				}
+				if restriction.RestrictionInfo == nil {
+					return nil, false
+				}
+				restricted, ok := in.zoneSet(restriction.RestrictionInfo.Zones)
+				if !ok {
+					return nil, false
+				}
+				available &^= restricted
+			}
+		}
+		return available.toMap(), true
+	}
+	return nil, true
+}
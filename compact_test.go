@@ -0,0 +1,309 @@
+package skewer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newZoneSet(t *testing.T) {
+	cases := map[string]struct {
+		zones    []string
+		expected map[string]bool
+		ok       bool
+	}{
+		"should convert numeric zones": {
+			zones:    []string{"3", "1", "2"},
+			expected: map[string]bool{"1": true, "2": true, "3": true},
+			ok:       true,
+		},
+		"should convert no zones": {
+			zones:    []string{},
+			expected: map[string]bool{},
+			ok:       true,
+		},
+		"should reject names": {
+			zones: []string{"eastus"},
+		},
+		"should reject non-canonical numbers": {
+			zones: []string{"01"},
+		},
+		"should reject large numbers": {
+			zones: []string{"64"},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			set, ok := newZoneSet(tc.zones)
+			if ok != tc.ok {
+				t.Fatalf("expected ok to be %t", tc.ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tc.expected, set.toMap()); diff != "" {
+				t.Errorf("expected zones to match: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_interner_compact(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edge := []compute.ResourceSku{
+		{},
+		{
+			Name:         to.StringPtr("empty"),
+			Locations:    &[]string{},
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{{Zones: &[]string{}, ZoneDetails: &[]compute.ResourceSkuZoneDetails{}}},
+			Capabilities: &[]compute.ResourceSkuCapabilities{{Name: to.StringPtr("nil value")}, {Value: to.StringPtr("nil name")}},
+			Restrictions: &[]compute.ResourceSkuRestrictions{{Type: compute.Zone}},
+		},
+		{
+			Name:         to.StringPtr("nil lists"),
+			Locations:    new([]string),
+			Capabilities: new([]compute.ResourceSkuCapabilities),
+			Restrictions: new([]compute.ResourceSkuRestrictions),
+			LocationInfo: new([]compute.ResourceSkuLocationInfo),
+		},
+	}
+	original := Wrap(append(dataWrapper.Value, edge...))
+
+	in := newInterner()
+	compacted, _ := in.compact(original)
+	if diff := cmp.Diff(original, compacted); diff != "" {
+		t.Fatalf("expected compact skus to equal the originals: %s", diff)
+	}
+
+	// The same data listed for another location shares everything but
+	// the location specific parts.
+	relocated, _ := in.compact(Wrap(newRelocatedSkus(t, "westus")))
+	for i := range relocated {
+		if relocated[i].Name != compacted[i].Name {
+			t.Fatalf("expected %s to share its name", compacted[i].GetName())
+		}
+		if relocated[i].Capabilities != compacted[i].Capabilities {
+			t.Fatalf("expected %s to share its capabilities", compacted[i].GetName())
+		}
+	}
+}
+
+func Test_interner_release(t *testing.T) {
+	in := newInterner()
+	eastus, eastusHeld := in.compact(Wrap(newRelocatedSkus(t, "eastus")))
+	westus, westusHeld := in.compact(Wrap(newRelocatedSkus(t, "westus")))
+
+	in.release(eastusHeld)
+	for i := range westus {
+		if capabilities := westus[i].Capabilities; capabilities != nil && in.capabilities[mustCapabilitiesKey(*capabilities)] == nil {
+			t.Fatalf("expected %s to keep capabilities shared with released data", westus[i].GetName())
+		}
+	}
+	if _, ok := in.strings["eastus"]; ok {
+		t.Errorf("expected strings only held by released data to be dropped")
+	}
+	if _, ok := in.zoneSet((*eastus[0].LocationInfo)[0].Zones); ok && (*eastus[0].LocationInfo)[0].Zones != (*westus[0].LocationInfo)[0].Zones {
+		t.Errorf("expected zone sets only held by released data to be dropped")
+	}
+
+	in.release(westusHeld)
+	remaining := len(in.strings) + len(in.stringLists) + len(in.capabilities) + len(in.locationInfo) + len(in.restrictions) + len(in.zoneSets)
+	if diff := cmp.Diff(0, remaining); diff != "" {
+		t.Errorf("expected no entries once all data is released: %s", diff)
+	}
+}
+
+func mustCapabilitiesKey(list []compute.ResourceSkuCapabilities) string {
+	var key strings.Builder
+	writeCapabilitiesKey(&key, list)
+	return key.String()
+}
+
+func Test_Cache_WithCompactStorage_releasesReplacedData(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{skus: newRelocatedSkus(t, "eastus")}
+	cache, err := NewCache(ctx, WithClient(client), WithCompactStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.skus = newRelocatedSkus(t, "westus")
+	if _, err := cache.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.interner.strings["eastus"]; ok {
+		t.Errorf("expected values of replaced data to be released")
+	}
+	if _, ok := cache.interner.strings["westus"]; !ok {
+		t.Errorf("expected values of current data to be held")
+	}
+}
+
+func Test_MultiLocationCache_WithCompactStorage(t *testing.T) {
+	ctx := context.Background()
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMultiLocationCache(WithClient(&fakeClient{skus: dataWrapper.Value}), WithCompactStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	eastus, err := m.Cache(ctx, "eastus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	westus, err := m.Cache(ctx, "westus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eastus.interner == nil || eastus.interner != westus.interner {
+		t.Fatal("expected locations to share an interner")
+	}
+	if eastus.List(ctx)[0].Capabilities != westus.List(ctx)[0].Capabilities {
+		t.Errorf("expected locations to share interned capabilities")
+	}
+
+	other, err := NewStaticCache(nil, WithCompactStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.interner == eastus.interner {
+		t.Errorf("expected other caches not to share the interner")
+	}
+}
+
+func Test_Cache_WithCompactStorage(t *testing.T) {
+	ctx := context.Background()
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := Wrap(dataWrapper.Value)
+
+	cache, err := NewCache(ctx, WithClient(&fakeClient{skus: dataWrapper.Value}), WithLocation("eastus"), WithCompactStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	compacted := cache.List(ctx)
+	if diff := cmp.Diff(plain, compacted); diff != "" {
+		t.Fatalf("expected compact cache to hold equal skus: %s", diff)
+	}
+
+	capabilities := []string{VCPUs, MemoryGB, CachedDiskBytes, "MaxDataDiskCount", "HyperVGenerations", "MaxSizeGiB", "missing"}
+	for i := range compacted {
+		for _, name := range capabilities {
+			expectedInt, expectedIntErr := plain[i].GetCapabilityIntegerQuantity(name)
+			gotInt, gotIntErr := compacted[i].GetCapabilityIntegerQuantity(name)
+			if expectedInt != gotInt || fmt.Sprint(expectedIntErr) != fmt.Sprint(gotIntErr) {
+				t.Errorf("%s %s: expected integer %d (%v), got %d (%v)", compacted[i].GetName(), name, expectedInt, expectedIntErr, gotInt, gotIntErr)
+			}
+			expectedFloat, expectedFloatErr := plain[i].GetCapabilityFloatQuantity(name)
+			gotFloat, gotFloatErr := compacted[i].GetCapabilityFloatQuantity(name)
+			if expectedFloat != gotFloat || fmt.Sprint(expectedFloatErr) != fmt.Sprint(gotFloatErr) {
+				t.Errorf("%s %s: expected float %f (%v), got %f (%v)", compacted[i].GetName(), name, expectedFloat, expectedFloatErr, gotFloat, gotFloatErr)
+			}
+		}
+		if compacted[i].LocationInfo == nil || !compacted[i].IsResourceType(VirtualMachines) {
+			continue
+		}
+		if diff := cmp.Diff(plain[i].AvailabilityZones("eastus"), cache.skuAvailabilityZones(&compacted[i])); diff != "" {
+			t.Errorf("%s: expected availability zones to match: %s", compacted[i].GetName(), diff)
+		}
+	}
+
+	if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+		t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+	}
+}
+
+// relocatedData returns the eastus test data as if listed for another
+// location.
+func relocatedData(tb testing.TB, location string) []byte {
+	data, err := ioutil.ReadFile("./testdata/eastus.json")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return bytes.ReplaceAll(data, []byte(`"eastus"`), []byte(fmt.Sprintf("%q", location)))
+}
+
+func newRelocatedSkus(tb testing.TB, location string) []compute.ResourceSku {
+	wrapper := new(dataWrapper)
+	if err := json.Unmarshal(relocatedData(tb, location), wrapper); err != nil {
+		tb.Fatal(err)
+	}
+	return wrapper.Value
+}
+
+// BenchmarkMemory_30Regions reports the heap retained by caches holding
+// 30 locations of data, with and without compact storage.
+func BenchmarkMemory_30Regions(b *testing.B) {
+	regions := make([][]byte, 30)
+	for i := range regions {
+		regions[i] = relocatedData(b, fmt.Sprintf("region%02d", i))
+	}
+
+	cases := map[string]func() []CacheOption{
+		"plain": func() []CacheOption {
+			return nil
+		},
+		"compact": func() []CacheOption {
+			// A fresh interner measures the full cost of interning.
+			in := newInterner()
+			return []CacheOption{func(c *Cache) error {
+				c.interner = in
+				return nil
+			}}
+		},
+	}
+
+	for _, name := range []string{"plain", "compact"} {
+		opts := cases[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				runtime.GC()
+				var before runtime.MemStats
+				runtime.ReadMemStats(&before)
+				b.StartTimer()
+
+				options := opts()
+				caches := make([]*Cache, len(regions))
+				for i, data := range regions {
+					wrapper := new(dataWrapper)
+					if err := json.Unmarshal(data, wrapper); err != nil {
+						b.Fatal(err)
+					}
+					cache, err := NewStaticCache(Wrap(wrapper.Value), options...)
+					if err != nil {
+						b.Fatal(err)
+					}
+					caches[i] = cache
+				}
+
+				b.StopTimer()
+				runtime.GC()
+				var after runtime.MemStats
+				runtime.ReadMemStats(&after)
+				runtime.KeepAlive(caches)
+				b.ReportMetric(float64(int64(after.HeapAlloc)-int64(before.HeapAlloc))/(1<<20), "MiB-retained")
+				b.StartTimer()
+			}
+		})
+	}
+}
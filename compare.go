@@ -0,0 +1,258 @@
+package skewer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+// Availability summarizes whether a sku may be deployed in a location.
+type Availability string
+
+const (
+	// Available indicates the sku is listed in the location and not
+	// restricted there. Some zones may still be restricted.
+	Available Availability = "Available"
+	// Restricted indicates the sku is listed in the location, but a
+	// restriction prevents deploying it anywhere in the location.
+	Restricted Availability = "Restricted"
+	// Absent indicates the sku is not listed in the location.
+	Absent Availability = "Absent"
+)
+
+// RegionAvailability is a cell of a RegionMatrix.
+type RegionAvailability struct {
+	Status Availability
+	// Zones lists the sorted zones where the sku may be deployed.
+	Zones []string
+	// RestrictedZones lists the sorted zones listed for the sku where a
+	// zone restriction prevents deploying it.
+	RestrictedZones []string
+	// ReasonCode is the reason for a location or zone restriction.
+	ReasonCode compute.ResourceSkuRestrictionsReasonCode
+}
+
+// RegionMatrixRow holds the availability of one sku across locations.
+type RegionMatrixRow struct {
+	Name string
+	Size string
+	// Availability maps each compared location to the availability of
+	// the sku there.
+	Availability map[string]RegionAvailability
+}
+
+// Consistent returns true when the sku has the same status, zones and
+// restricted zones in every location.
+func (r RegionMatrixRow) Consistent() bool {
+	var first *RegionAvailability
+	for location := range r.Availability {
+		cell := r.Availability[location]
+		if first == nil {
+			first = &cell
+			continue
+		}
+		if cell.Status != first.Status ||
+			!equalStrings(cell.Zones, first.Zones) ||
+			!equalStrings(cell.RestrictedZones, first.RestrictedZones) {
+			return false
+		}
+	}
+	return true
+}
+
+// RegionMatrix compares the skus of one resource type across locations:
+// for each sku and location, whether the sku is available, restricted
+// or absent.
+type RegionMatrix struct {
+	ResourceType string
+	// Locations are the compared locations, in the order provided.
+	Locations []string
+	// Rows holds one row per sku listed in any location, ordered by
+	// name.
+	Rows []RegionMatrixRow
+}
+
+// ErrCompareLocation will be returned when a cache cannot take part in
+// a comparison across locations.
+type ErrCompareLocation struct {
+	reason string
+}
+
+func (e *ErrCompareLocation) Error() string {
+	return fmt.Sprintf("could not compare locations: %s", e.reason)
+}
+
+// Compare builds a RegionMatrix of the skus of resourceType held by
+// caches. Each cache must be created with WithLocation, and locations
+// may not repeat.
+func Compare(ctx context.Context, resourceType string, caches ...*Cache) (RegionMatrix, error) {
+	matrix := RegionMatrix{ResourceType: resourceType}
+
+	var (
+		rows = make(map[string]*RegionMatrixRow)
+		seen = make(map[string]bool)
+	)
+
+	for _, cache := range caches {
+		location := normalizeLocation(cache.location)
+		if location == "" {
+			return RegionMatrix{}, &ErrCompareLocation{"every cache must be created with WithLocation"}
+		}
+		if seen[location] {
+			return RegionMatrix{}, &ErrCompareLocation{fmt.Sprintf("location '%s' was provided more than once", location)}
+		}
+		seen[location] = true
+		matrix.Locations = append(matrix.Locations, location)
+
+		skus := cache.List(ctx, ResourceTypeFilter(resourceType))
+		for i := range skus {
+			key := strings.ToLower(skus[i].GetName() + "/" + derefString(skus[i].Size))
+			row, ok := rows[key]
+			if !ok {
+				row = &RegionMatrixRow{
+					Name:         skus[i].GetName(),
+					Size:         derefString(skus[i].Size),
+					Availability: make(map[string]RegionAvailability),
+				}
+				rows[key] = row
+			}
+			row.Availability[location] = skus[i].availabilityIn(location)
+		}
+	}
+
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		row := rows[key]
+		for _, location := range matrix.Locations {
+			if _, ok := row.Availability[location]; !ok {
+				row.Availability[location] = RegionAvailability{Status: Absent}
+			}
+		}
+		matrix.Rows = append(matrix.Rows, *row)
+	}
+
+	return matrix, nil
+}
+
+// availabilityIn summarizes the availability of a sku in a location.
+func (s *SKU) availabilityIn(location string) RegionAvailability {
+	if !s.isListedIn(location) {
+		return RegionAvailability{Status: Absent}
+	}
+
+	restricted, restrictedZones, reasonCode := s.restrictionsFor(location)
+	if restricted {
+		return RegionAvailability{Status: Restricted, ReasonCode: reasonCode}
+	}
+
+	listed := s.zones(location)
+	zones := make(map[string]bool, len(listed))
+	restrictedListed := make(map[string]bool)
+	for zone := range listed {
+		if restrictedZones[zone] {
+			restrictedListed[zone] = true
+			continue
+		}
+		zones[zone] = true
+	}
+
+	return RegionAvailability{
+		Status:          Available,
+		Zones:           sortedKeys(zones),
+		RestrictedZones: sortedKeys(restrictedListed),
+		ReasonCode:      reasonCode,
+	}
+}
+
+// isListedIn returns true when the sku lists the location.
+func (s *SKU) isListedIn(location string) bool {
+	for _, candidate := range s.locations() {
+		if strings.EqualFold(candidate, location) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the availability of a sku in a location, matching the
+// name case-insensitively. Skus which are not in the matrix, such as
+// those of another resource type, are reported as absent.
+func (m RegionMatrix) Get(name, location string) RegionAvailability {
+	location = normalizeLocation(location)
+	for _, row := range m.Rows {
+		if strings.EqualFold(row.Name, name) {
+			if cell, ok := row.Availability[location]; ok {
+				return cell
+			}
+		}
+	}
+	return RegionAvailability{Status: Absent}
+}
+
+// Missing returns the names of skus available in one location but
+// restricted or absent in another. For example, Missing("eastus",
+// "westeurope") lists the sizes which cannot follow a deployment from
+// eastus to westeurope.
+func (m RegionMatrix) Missing(available, missing string) []string {
+	available, missing = normalizeLocation(available), normalizeLocation(missing)
+	var names []string
+	for _, row := range m.Rows {
+		if row.Availability[available].Status == Available && row.Availability[missing].Status != Available {
+			names = append(names, row.Name)
+		}
+	}
+	return names
+}
+
+// AvailableEverywhere returns the names of skus available in every
+// compared location.
+func (m RegionMatrix) AvailableEverywhere() []string {
+	var names []string
+	for _, row := range m.Rows {
+		everywhere := true
+		for _, location := range m.Locations {
+			if row.Availability[location].Status != Available {
+				everywhere = false
+				break
+			}
+		}
+		if everywhere {
+			names = append(names, row.Name)
+		}
+	}
+	return names
+}
+
+// Differences returns the rows whose availability, zones or restricted
+// zones differ between locations.
+func (m RegionMatrix) Differences() []RegionMatrixRow {
+	var rows []RegionMatrixRow
+	for _, row := range m.Rows {
+		if !row.Consistent() {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Compare builds a RegionMatrix of the skus of resourceType across
+// locations, loading any location not yet cached.
+func (m *MultiLocationCache) Compare(ctx context.Context, resourceType string, locations ...string) (RegionMatrix, error) {
+	caches := make([]*Cache, 0, len(locations))
+	for _, location := range locations {
+		cache, err := m.Cache(ctx, location)
+		if err != nil {
+			return RegionMatrix{}, err
+		}
+		caches = append(caches, cache)
+	}
+	return Compare(ctx, resourceType, caches...)
+}
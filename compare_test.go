@@ -0,0 +1,168 @@
+package skewer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newFakeRegionCaches(t *testing.T) []*Cache {
+	regions := map[string][]compute.ResourceSku{
+		"eastus": {
+			newFakeLocationSku("Standard_D4s_v3", "eastus", "1", "2", "3"),
+			newFakeLocationSku("Standard_NC6", "eastus", "1", "2", "3"),
+			newFakeRestrictedSku("Standard_E4s_v3", "eastus", newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2")),
+			newFakeLocationSku("Standard_F4s_v2", "eastus", "1", "2", "3"),
+		},
+		"westeurope": {
+			newFakeLocationSku("Standard_D4s_v3", "westeurope", "3", "2", "1"),
+			newFakeRestrictedSku("Standard_E4s_v3", "westeurope"),
+			newFakeRestrictedSku("Standard_F4s_v2", "westeurope", newFakeLocationRestriction("westeurope", compute.QuotaID)),
+			newFakeLocationSku("Standard_M8ms", "westeurope"),
+		},
+	}
+
+	var caches []*Cache
+	for _, location := range []string{"eastus", "westeurope"} {
+		cache, err := NewStaticCache(Wrap(regions[location]), WithLocation(location))
+		if err != nil {
+			t.Fatal(err)
+		}
+		caches = append(caches, cache)
+	}
+	return caches
+}
+
+// nolint:funlen
+func Test_Compare(t *testing.T) {
+	ctx := context.Background()
+	matrix, err := Compare(ctx, VirtualMachines, newFakeRegionCaches(t)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"eastus", "westeurope"}, matrix.Locations); diff != "" {
+		t.Errorf("expected locations to match: %s", diff)
+	}
+
+	zones := []string{"1", "2", "3"}
+	cases := map[string]struct {
+		name     string
+		location string
+		expected RegionAvailability
+	}{
+		"available": {
+			name:     "Standard_D4s_v3",
+			location: "westeurope",
+			expected: RegionAvailability{Status: Available, Zones: zones, RestrictedZones: []string{}},
+		},
+		"absent": {
+			name:     "Standard_NC6",
+			location: "westeurope",
+			expected: RegionAvailability{Status: Absent},
+		},
+		"zone restricted": {
+			name:     "Standard_E4s_v3",
+			location: "eastus",
+			expected: RegionAvailability{Status: Available, Zones: []string{"1", "3"}, RestrictedZones: []string{"2"}, ReasonCode: compute.NotAvailableForSubscription},
+		},
+		"location restricted": {
+			name:     "standard_f4s_v2",
+			location: "WestEurope",
+			expected: RegionAvailability{Status: Restricted, ReasonCode: compute.QuotaID},
+		},
+		"without zones": {
+			name:     "Standard_M8ms",
+			location: "westeurope",
+			expected: RegionAvailability{Status: Available, Zones: []string{}, RestrictedZones: []string{}},
+		},
+		"unknown": {
+			name:     "Standard_Unknown",
+			location: "eastus",
+			expected: RegionAvailability{Status: Absent},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, matrix.Get(tc.name, tc.location)); diff != "" {
+				t.Errorf("expected availability to match: %s", diff)
+			}
+		})
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"Standard_F4s_v2", "Standard_NC6"}, matrix.Missing("eastus", "westeurope")); diff != "" {
+			t.Errorf("expected missing skus to match: %s", diff)
+		}
+		if diff := cmp.Diff([]string{"Standard_M8ms"}, matrix.Missing("westeurope", "eastus")); diff != "" {
+			t.Errorf("expected missing skus to match: %s", diff)
+		}
+	})
+
+	t.Run("available everywhere", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"Standard_D4s_v3", "Standard_E4s_v3"}, matrix.AvailableEverywhere()); diff != "" {
+			t.Errorf("expected skus available everywhere to match: %s", diff)
+		}
+	})
+
+	t.Run("differences", func(t *testing.T) {
+		var names []string
+		for _, row := range matrix.Differences() {
+			names = append(names, row.Name)
+		}
+		if diff := cmp.Diff([]string{"Standard_E4s_v3", "Standard_F4s_v2", "Standard_M8ms", "Standard_NC6"}, names); diff != "" {
+			t.Errorf("expected differing skus to match: %s", diff)
+		}
+	})
+}
+
+func Test_Compare_Errors(t *testing.T) {
+	ctx := context.Background()
+	withoutLocation, err := NewStaticCache(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caches := newFakeRegionCaches(t)
+
+	cases := map[string][]*Cache{
+		"should require locations":       {caches[0], withoutLocation},
+		"should reject duplicate caches": {caches[0], caches[1], caches[0]},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			errCompare := &ErrCompareLocation{}
+			if _, err := Compare(ctx, VirtualMachines, tc...); !errors.As(err, &errCompare) {
+				t.Errorf("expected ErrCompareLocation, got: %v", err)
+			}
+		})
+	}
+}
+
+func Test_MultiLocationCache_Compare(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeLocationClient{
+		skus: map[string][]compute.ResourceSku{
+			"eastus":     {newFakeLocationSku("Standard_NC6", "eastus", "1")},
+			"westeurope": {},
+		},
+	}
+	cache, err := NewMultiLocationCache(WithClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matrix, err := cache.Compare(ctx, VirtualMachines, "EastUS", "westeurope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"Standard_NC6"}, matrix.Missing("eastus", "westeurope")); diff != "" {
+		t.Errorf("expected missing skus to match: %s", diff)
+	}
+}
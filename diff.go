@@ -0,0 +1,331 @@
+package skewer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+// SKUDiff describes the differences between two sets of skus. Skus are
+// matched by resource type, name, size and locations, ignoring case.
+// Skus listed more than once with the same identity are matched in the
+// order they are listed, so duplicates are compared rather than lost.
+type SKUDiff struct {
+	// Added holds skus only present in the new set.
+	Added []SKU
+	// Removed holds skus only present in the old set.
+	Removed []SKU
+	// Changed holds skus present in both sets whose capabilities,
+	// zones or restrictions differ.
+	Changed []SKUChange
+}
+
+// SKUChange describes the differences between two versions of a sku.
+type SKUChange struct {
+	Old SKU
+	New SKU
+	// Capabilities holds capability changes, ordered by name.
+	Capabilities []CapabilityChange
+	// Zones holds changes to the zones listed per location, ordered by
+	// location.
+	Zones []ZoneChange
+	// Restrictions holds restrictions added or lifted, ordered by
+	// location, then zone.
+	Restrictions []RestrictionChange
+}
+
+// CapabilityChange describes a capability whose value changed, or which
+// was added or removed.
+type CapabilityChange struct {
+	Name string
+	// OldValue is empty when the capability was added.
+	OldValue string
+	// NewValue is empty when the capability was removed.
+	NewValue string
+}
+
+// ZoneChange describes a change to the zones listed for a location.
+type ZoneChange struct {
+	Location string
+	// Previous and Current are the sorted zones before and after the
+	// change.
+	Previous []string
+	Current  []string
+}
+
+// RestrictionChange describes a restriction added or lifted. Restrictions
+// are compared per location and zone: a zone restriction covering three
+// zones which appears produces three changes.
+type RestrictionChange struct {
+	// Type is either RestrictionAdded or RestrictionLifted.
+	Type     SKUChangeType
+	Location string
+	// Zone is the zone of a zone restriction. It is empty for
+	// restrictions which apply to the whole location.
+	Zone       string
+	ReasonCode compute.ResourceSkuRestrictionsReasonCode
+}
+
+// IsEmpty returns true when the sets of skus are equivalent.
+func (d SKUDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff returns the differences between two sets of skus, such as the
+// data of a cache before and after a refresh. Added, Removed and
+// Changed are ordered by resource type, name, size and locations.
+func Diff(old, updated []SKU) SKUDiff {
+	before := indexSKUs(old)
+	after := indexSKUs(updated)
+
+	keys := make([]skuKey, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].identity != keys[j].identity {
+			return keys[i].identity < keys[j].identity
+		}
+		return keys[i].occurrence < keys[j].occurrence
+	})
+
+	var diff SKUDiff
+	for _, key := range keys {
+		oldSKU, hadOld := before[key]
+		newSKU, hasNew := after[key]
+		switch {
+		case !hadOld:
+			diff.Added = append(diff.Added, newSKU)
+		case !hasNew:
+			diff.Removed = append(diff.Removed, oldSKU)
+		default:
+			if change := diffSKU(oldSKU, newSKU); !change.isEmpty() {
+				diff.Changed = append(diff.Changed, change)
+			}
+		}
+	}
+
+	return diff
+}
+
+func (c SKUChange) isEmpty() bool {
+	return len(c.Capabilities) == 0 && len(c.Zones) == 0 && len(c.Restrictions) == 0
+}
+
+// diffSKU returns the differences between two versions of the same sku.
+func diffSKU(old, updated SKU) SKUChange {
+	change := SKUChange{
+		Old:          old,
+		New:          updated,
+		Capabilities: diffCapabilities(old.capabilityValues(), updated.capabilityValues()),
+	}
+
+	for _, location := range unionStrings(old.locations(), updated.locations()) {
+		oldZones, newZones := sortedKeys(old.zones(location)), sortedKeys(updated.zones(location))
+		if !equalStrings(oldZones, newZones) {
+			change.Zones = append(change.Zones, ZoneChange{
+				Location: location,
+				Previous: oldZones,
+				Current:  newZones,
+			})
+		}
+
+		oldRestrictions, newRestrictions := old.restrictionsIn(location), updated.restrictionsIn(location)
+		for _, restriction := range oldRestrictions {
+			if !containsRestriction(newRestrictions, restriction) {
+				restriction.Type = RestrictionLifted
+				change.Restrictions = append(change.Restrictions, restriction)
+			}
+		}
+		for _, restriction := range newRestrictions {
+			if !containsRestriction(oldRestrictions, restriction) {
+				restriction.Type = RestrictionAdded
+				change.Restrictions = append(change.Restrictions, restriction)
+			}
+		}
+	}
+
+	return change
+}
+
+// diffCapabilities compares capability values by name.
+func diffCapabilities(old, updated map[string]string) []CapabilityChange {
+	var changes []CapabilityChange
+	for _, name := range unionStrings(keysOf(old), keysOf(updated)) {
+		if old[name] != updated[name] {
+			changes = append(changes, CapabilityChange{
+				Name:     name,
+				OldValue: old[name],
+				NewValue: updated[name],
+			})
+		}
+	}
+	return changes
+}
+
+// capabilityValues maps capability names to values.
+func (s *SKU) capabilityValues() map[string]string {
+	values := make(map[string]string)
+	if s.Capabilities == nil {
+		return values
+	}
+	for _, capability := range *s.Capabilities {
+		if capability.Name != nil && capability.Value != nil {
+			values[*capability.Name] = *capability.Value
+		}
+	}
+	return values
+}
+
+// skuKey identifies a sku within a listing: its identity, and how many
+// skus with the same identity were listed before it.
+type skuKey struct {
+	identity   string
+	occurrence int
+}
+
+// indexSKUs maps skus by key, keeping every sku.
+func indexSKUs(skus []SKU) map[skuKey]SKU {
+	index := make(map[skuKey]SKU, len(skus))
+	occurrences := make(map[string]int, len(skus))
+	for i := range skus {
+		identity := skus[i].identity()
+		index[skuKey{identity, occurrences[identity]}] = skus[i]
+		occurrences[identity]++
+	}
+	return index
+}
+
+// identity distinguishes skus within a listing. Disk skus share names
+// across sizes, and unfiltered listings repeat names across locations.
+func (s *SKU) identity() string {
+	parts := []string{
+		s.GetResourceType(),
+		s.GetName(),
+		derefString(s.Size),
+	}
+	if s.Locations != nil {
+		parts = append(parts, *s.Locations...)
+	}
+	return strings.ToLower(strings.Join(parts, "/"))
+}
+
+// locations returns the sorted, lower-cased locations a sku is listed
+// in, including those only present in its location info.
+func (s *SKU) locations() []string {
+	set := make(map[string]bool)
+	if s.Locations != nil {
+		for _, location := range *s.Locations {
+			set[strings.ToLower(location)] = true
+		}
+	}
+	if s.LocationInfo != nil {
+		for _, info := range *s.LocationInfo {
+			if info.Location != nil {
+				set[strings.ToLower(*info.Location)] = true
+			}
+		}
+	}
+	return sortedKeys(set)
+}
+
+// restrictionsIn returns the restrictions applying to a location, split
+// per zone and ordered by zone. The Type of each change is left empty.
+func (s *SKU) restrictionsIn(location string) []RestrictionChange {
+	var restrictions []RestrictionChange
+	if s.Restrictions == nil {
+		return restrictions
+	}
+
+	for _, restriction := range *s.Restrictions {
+		if !restrictionAppliesTo(restriction, location) {
+			continue
+		}
+		switch restriction.Type {
+		case compute.Location:
+			restrictions = append(restrictions, RestrictionChange{
+				Location:   location,
+				ReasonCode: restriction.ReasonCode,
+			})
+		case compute.Zone:
+			if restriction.RestrictionInfo == nil || restriction.RestrictionInfo.Zones == nil {
+				continue
+			}
+			for _, zone := range *restriction.RestrictionInfo.Zones {
+				restrictions = append(restrictions, RestrictionChange{
+					Location:   location,
+					Zone:       zone,
+					ReasonCode: restriction.ReasonCode,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(restrictions, func(i, j int) bool {
+		return restrictions[i].Zone < restrictions[j].Zone
+	})
+
+	return restrictions
+}
+
+func containsRestriction(restrictions []RestrictionChange, restriction RestrictionChange) bool {
+	for _, candidate := range restrictions {
+		if candidate == restriction {
+			return true
+		}
+	}
+	return false
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func keysOf(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unionStrings(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		set[s] = true
+	}
+	return sortedKeys(set)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
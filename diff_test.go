@@ -0,0 +1,189 @@
+package skewer
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func withCapabilities(sku compute.ResourceSku, pairs ...string) compute.ResourceSku {
+	capabilities := []compute.ResourceSkuCapabilities{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		capabilities = append(capabilities, compute.ResourceSkuCapabilities{
+			Name:  to.StringPtr(pairs[i]),
+			Value: to.StringPtr(pairs[i+1]),
+		})
+	}
+	sku.Capabilities = &capabilities
+	return sku
+}
+
+// diffSummary reduces a diff to sku names and changes for comparison.
+type diffSummary struct {
+	Added        []string
+	Removed      []string
+	Changed      []string
+	Capabilities []CapabilityChange
+	Zones        []ZoneChange
+	Restrictions []RestrictionChange
+}
+
+func summarizeDiff(diff SKUDiff) diffSummary {
+	var summary diffSummary
+	for i := range diff.Added {
+		summary.Added = append(summary.Added, diff.Added[i].GetName()+"@"+diff.Added[i].GetLocation())
+	}
+	for i := range diff.Removed {
+		summary.Removed = append(summary.Removed, diff.Removed[i].GetName()+"@"+diff.Removed[i].GetLocation())
+	}
+	for _, change := range diff.Changed {
+		summary.Changed = append(summary.Changed, change.New.GetName())
+		summary.Capabilities = append(summary.Capabilities, change.Capabilities...)
+		summary.Zones = append(summary.Zones, change.Zones...)
+		summary.Restrictions = append(summary.Restrictions, change.Restrictions...)
+	}
+	return summary
+}
+
+// nolint:funlen
+func Test_Diff(t *testing.T) {
+	size := "Standard_D4s_v3"
+
+	cases := map[string]struct {
+		old      []compute.ResourceSku
+		updated  []compute.ResourceSku
+		expected diffSummary
+	}{
+		"should report nothing for empty sets": {},
+		"should key skus by location": {
+			old:     []compute.ResourceSku{newFakeLocationSku(size, "eastus")},
+			updated: []compute.ResourceSku{newFakeLocationSku(size, "eastus"), newFakeLocationSku(size, "westus")},
+			expected: diffSummary{
+				Added: []string{size + "@westus"},
+			},
+		},
+		"should key disks by size": {
+			old:     []compute.ResourceSku{newFakeDiskSku("Premium_LRS", "P1"), newFakeDiskSku("Premium_LRS", "P2")},
+			updated: []compute.ResourceSku{newFakeDiskSku("Premium_LRS", "P1"), newFakeDiskSku("Premium_LRS", "P3")},
+			expected: diffSummary{
+				Added:   []string{"Premium_LRS@eastus"},
+				Removed: []string{"Premium_LRS@eastus"},
+			},
+		},
+		"should keep skus listed twice": {
+			old: []compute.ResourceSku{
+				withCapabilities(newFakeLocationSku(size, "eastus"), "vCPUs", "4"),
+				withCapabilities(newFakeLocationSku(size, "eastus"), "vCPUs", "8"),
+			},
+			updated: []compute.ResourceSku{
+				withCapabilities(newFakeLocationSku(size, "eastus"), "vCPUs", "4"),
+				withCapabilities(newFakeLocationSku(size, "eastus"), "vCPUs", "16"),
+				withCapabilities(newFakeLocationSku(size, "eastus"), "vCPUs", "32"),
+			},
+			expected: diffSummary{
+				Added:   []string{size + "@eastus"},
+				Changed: []string{size},
+				Capabilities: []CapabilityChange{
+					{Name: "vCPUs", OldValue: "8", NewValue: "16"},
+				},
+			},
+		},
+		"should report removed duplicates": {
+			old:     []compute.ResourceSku{newFakeLocationSku(size, "eastus"), newFakeLocationSku(size, "eastus")},
+			updated: []compute.ResourceSku{newFakeLocationSku(size, "eastus")},
+			expected: diffSummary{
+				Removed: []string{size + "@eastus"},
+			},
+		},
+		"should report capability changes": {
+			old: []compute.ResourceSku{withCapabilities(newFakeLocationSku(size, "eastus"),
+				"MaxDataDiskCount", "8", "vCPUs", "4", "LowPriorityCapable", "True")},
+			updated: []compute.ResourceSku{withCapabilities(newFakeLocationSku(size, "eastus"),
+				"MaxDataDiskCount", "16", "vCPUs", "4", "AcceleratedNetworkingEnabled", "True")},
+			expected: diffSummary{
+				Changed: []string{size},
+				Capabilities: []CapabilityChange{
+					{Name: "AcceleratedNetworkingEnabled", NewValue: "True"},
+					{Name: "LowPriorityCapable", OldValue: "True"},
+					{Name: "MaxDataDiskCount", OldValue: "8", NewValue: "16"},
+				},
+			},
+		},
+		"should report zone and restriction changes": {
+			old: []compute.ResourceSku{newFakeRestrictedSku(size, "eastus",
+				newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "1"),
+			)},
+			updated: func() []compute.ResourceSku {
+				sku := newFakeRestrictedSku(size, "eastus",
+					newFakeZoneRestriction("eastus", compute.QuotaID, "1"),
+					newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "3"),
+				)
+				(*sku.LocationInfo)[0].Zones = &[]string{"1", "3"}
+				return []compute.ResourceSku{sku}
+			}(),
+			expected: diffSummary{
+				Changed: []string{size},
+				Zones: []ZoneChange{
+					{Location: "eastus", Previous: []string{"1", "2", "3"}, Current: []string{"1", "3"}},
+				},
+				Restrictions: []RestrictionChange{
+					{Type: RestrictionLifted, Location: "eastus", Zone: "1", ReasonCode: compute.NotAvailableForSubscription},
+					{Type: RestrictionAdded, Location: "eastus", Zone: "1", ReasonCode: compute.QuotaID},
+					{Type: RestrictionAdded, Location: "eastus", Zone: "3", ReasonCode: compute.NotAvailableForSubscription},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			diff := Diff(Wrap(tc.old), Wrap(tc.updated))
+			if diff := cmp.Diff(tc.expected, summarizeDiff(diff)); diff != "" {
+				t.Errorf("expected diff to match: %s", diff)
+			}
+			if got, expected := diff.IsEmpty(), cmp.Equal(tc.expected, diffSummary{}); got != expected {
+				t.Errorf("expected IsEmpty to be %t", expected)
+			}
+		})
+	}
+}
+
+func Test_Diff_Data(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := Wrap(dataWrapper.Value)
+
+	if diff := Diff(old, Wrap(dataWrapper.Value)); !diff.IsEmpty() {
+		t.Errorf("expected no differences between identical data, got %+v", summarizeDiff(diff))
+	}
+
+	updated, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range updated.Value {
+		sku := SKU(updated.Value[i])
+		if sku.GetName() != "Standard_D4s_v3" {
+			continue
+		}
+		for j, capability := range *sku.Capabilities {
+			if *capability.Name == "MaxDataDiskCount" {
+				(*sku.Capabilities)[j].Value = to.StringPtr("16")
+			}
+		}
+	}
+
+	expected := diffSummary{
+		Changed:      []string{"Standard_D4s_v3"},
+		Capabilities: []CapabilityChange{{Name: "MaxDataDiskCount", OldValue: "8", NewValue: "16"}},
+	}
+	if diff := cmp.Diff(expected, summarizeDiff(Diff(old, Wrap(updated.Value))), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("expected diff to match: %s", diff)
+	}
+}
@@ -0,0 +1,112 @@
+package skewer
+
+// DiskSKU is a typed view of a managed disk sku, such as Premium_LRS of
+// size P10, with capabilities parsed once. Optional capabilities, such
+// as burst limits which only some sizes list, are zero when not listed.
+type DiskSKU struct {
+	// Name is the storage account type, such as "Premium_LRS".
+	Name string
+	// Tier is the performance tier, such as "Premium".
+	Tier string
+	// Size is the disk size, such as "P10".
+	Size string
+
+	// MinSizeGiB and MaxSizeGiB bound the sizes billed as this sku. They
+	// are listed for every disk sku.
+	MinSizeGiB int64
+	MaxSizeGiB int64
+
+	MinIOps             int64
+	MaxIOps             int64
+	MinBandwidthMBps    int64
+	MaxBandwidthMBps    int64
+	MaxValueOfMaxShares int64
+
+	// The ReadWrite limits are those of ultra disks, which do not list
+	// the limits above.
+	MinIOpsReadWrite          int64
+	MaxIOpsReadWrite          int64
+	MinBandwidthMBpsReadWrite int64
+	MaxBandwidthMBpsReadWrite int64
+
+	MaxBurstIops               int64
+	MaxBurstBandwidthMBps      int64
+	MaxBurstDurationInMin      int64
+	BurstCreditBucketSizeInIO  int64
+	BurstCreditBucketSizeInGiB int64
+}
+
+// AsDisk returns a typed view of a disk sku. It errors if the sku is not
+// a disk, if MinSizeGiB or MaxSizeGiB are not listed, or if any listed
+// value is nil or could not be parsed.
+func (s *SKU) AsDisk() (DiskSKU, error) {
+	if !s.IsResourceType(Disks) {
+		return DiskSKU{}, &ErrResourceType{Disks, s.GetResourceType()}
+	}
+
+	r := &capabilityReader{list: s.Capabilities}
+	disk := DiskSKU{
+		Name: s.GetName(),
+		Tier: derefString(s.Tier),
+		Size: derefString(s.Size),
+
+		MinSizeGiB: r.integer(MinSizeGiB, true),
+		MaxSizeGiB: r.integer(MaxSizeGiB, true),
+
+		MinIOps:             r.integer(MinIOps, false),
+		MaxIOps:             r.integer(MaxIOps, false),
+		MinBandwidthMBps:    r.integer(MinBandwidthMBps, false),
+		MaxBandwidthMBps:    r.integer(MaxBandwidthMBps, false),
+		MaxValueOfMaxShares: r.integer(MaxValueOfMaxShares, false),
+
+		MinIOpsReadWrite:          r.integer(MinIOpsReadWrite, false),
+		MaxIOpsReadWrite:          r.integer(MaxIOpsReadWrite, false),
+		MinBandwidthMBpsReadWrite: r.integer(MinBandwidthMBpsReadWrite, false),
+		MaxBandwidthMBpsReadWrite: r.integer(MaxBandwidthMBpsReadWrite, false),
+
+		MaxBurstIops:               r.integer(MaxBurstIops, false),
+		MaxBurstBandwidthMBps:      r.integer(MaxBurstBandwidthMBps, false),
+		MaxBurstDurationInMin:      r.integer(MaxBurstDurationInMin, false),
+		BurstCreditBucketSizeInIO:  r.integer(BurstCreditBucketSizeInIO, false),
+		BurstCreditBucketSizeInGiB: r.integer(BurstCreditBucketSizeInGiB, false),
+	}
+	if r.err != nil {
+		return DiskSKU{}, r.err
+	}
+
+	return disk, nil
+}
+
+// DiskSizeAtLeast produces a filter function for disk skus which allow
+// disks of at least gib GiB.
+func DiskSizeAtLeast(gib int64) func(*SKU) bool {
+	return func(s *SKU) bool {
+		return s.IsResourceType(Disks) && s.hasCapacity(MaxSizeGiB, gib)
+	}
+}
+
+// DiskIOPSAtLeast produces a filter function for disk skus which allow
+// at least iops iops, including ultra disks when provisioned at most.
+func DiskIOPSAtLeast(iops int64) func(*SKU) bool {
+	return func(s *SKU) bool {
+		return s.IsResourceType(Disks) &&
+			(s.hasCapacity(MaxIOps, iops) || s.hasCapacity(MaxIOpsReadWrite, iops))
+	}
+}
+
+// DiskBandwidthAtLeast produces a filter function for disk skus which
+// allow at least mbps MBps of throughput, including ultra disks when
+// provisioned at most.
+func DiskBandwidthAtLeast(mbps int64) func(*SKU) bool {
+	return func(s *SKU) bool {
+		return s.IsResourceType(Disks) &&
+			(s.hasCapacity(MaxBandwidthMBps, mbps) || s.hasCapacity(MaxBandwidthMBpsReadWrite, mbps))
+	}
+}
+
+// hasCapacity is HasCapabilityWithCapacity, treating values which could
+// not be parsed as insufficient.
+func (s *SKU) hasCapacity(name string, value int64) bool {
+	ok, err := s.HasCapabilityWithCapacity(name, value)
+	return err == nil && ok
+}
@@ -0,0 +1,176 @@
+package skewer
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newFakeDiskSkuWithCapabilities(capabilities ...compute.ResourceSkuCapabilities) compute.ResourceSku {
+	return compute.ResourceSku{
+		ResourceType: to.StringPtr(Disks),
+		Name:         to.StringPtr("Premium_LRS"),
+		Size:         to.StringPtr("P1"),
+		Capabilities: &capabilities,
+	}
+}
+
+func Test_SKU_AsDisk(t *testing.T) {
+	cases := map[string]struct {
+		sku    compute.ResourceSku
+		expect DiskSKU
+		err    string
+	}{
+		"should parse premium size with burst limits": {
+			sku: testdataSKU(t, Disks, "Premium_LRS", "P10"),
+			expect: DiskSKU{
+				Name:                       "Premium_LRS",
+				Tier:                       "Premium",
+				Size:                       "P10",
+				MinSizeGiB:                 64,
+				MaxSizeGiB:                 128,
+				MinIOps:                    500,
+				MaxIOps:                    500,
+				MinBandwidthMBps:           100,
+				MaxBandwidthMBps:           100,
+				MaxValueOfMaxShares:        1,
+				MaxBurstIops:               3500,
+				MaxBurstBandwidthMBps:      170,
+				MaxBurstDurationInMin:      30,
+				BurstCreditBucketSizeInIO:  5400000,
+				BurstCreditBucketSizeInGiB: 123,
+			},
+		},
+		"should parse standard size without burst limits": {
+			sku: testdataSKU(t, Disks, "Standard_LRS", "S4"),
+			expect: DiskSKU{
+				Name:                "Standard_LRS",
+				Tier:                "Standard",
+				Size:                "S4",
+				MaxSizeGiB:          32,
+				MinIOps:             500,
+				MaxIOps:             500,
+				MinBandwidthMBps:    60,
+				MaxBandwidthMBps:    60,
+				MaxValueOfMaxShares: 1,
+			},
+		},
+		"should parse ultra disk": {
+			sku: testdataSKU(t, Disks, "UltraSSD_LRS", "U"),
+			expect: DiskSKU{
+				Name:                      "UltraSSD_LRS",
+				Tier:                      "Ultra",
+				Size:                      "U",
+				MinSizeGiB:                4,
+				MaxSizeGiB:                65536,
+				MaxValueOfMaxShares:       5,
+				MinIOpsReadWrite:          100,
+				MaxIOpsReadWrite:          160000,
+				MinBandwidthMBpsReadWrite: 1,
+				MaxBandwidthMBpsReadWrite: 2000,
+			},
+		},
+		"should error for other resource types": {
+			sku: testdataSKU(t, VirtualMachines, "Standard_D4s_v3", ""),
+			err: "expected sku of resource type 'disks', found 'virtualMachines'",
+		},
+		"should error when sizes are not listed": {
+			sku: newFakeDiskSkuWithCapabilities(
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MinSizeGiB), Value: to.StringPtr("0")},
+			),
+			err: "MaxSizeGiBCapabilityNotFound",
+		},
+		"should error when a value could not be parsed": {
+			sku: newFakeDiskSkuWithCapabilities(
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MinSizeGiB), Value: to.StringPtr("0")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MaxSizeGiB), Value: to.StringPtr("4")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MaxIOps), Value: to.StringPtr("fast")},
+			),
+			err: "MaxIOpsCapabilityValueParse: failed to parse string 'fast' as int64, error: 'strconv.ParseInt: parsing \"fast\": invalid syntax'", // nolint:lll
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sku := SKU(tc.sku)
+			disk, err := sku.AsDisk()
+			if tc.err != "" {
+				if err == nil {
+					t.Fatalf("expected failure with error '%s' but did not occur", tc.err)
+				}
+				if diff := cmp.Diff(tc.err, err.Error()); diff != "" {
+					t.Error(diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected success but failure occurred with error '%s'", err)
+			}
+			if diff := cmp.Diff(tc.expect, disk); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_DiskFilters(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := Wrap(dataWrapper.Value)
+
+	cases := map[string]struct {
+		filter FilterFn
+		expect []string
+	}{
+		"should find sizes allowing large disks": {
+			filter: DiskSizeAtLeast(16384),
+			expect: []string{
+				"Standard_LRS/S70",
+				"Standard_LRS/S80",
+				"Premium_LRS/P70",
+				"Premium_LRS/P80",
+				"StandardSSD_LRS/E70",
+				"StandardSSD_LRS/E80",
+				"UltraSSD_LRS/U",
+			},
+		},
+		"should find sizes allowing high iops, including ultra disks": {
+			filter: DiskIOPSAtLeast(20000),
+			expect: []string{"Premium_LRS/P80", "UltraSSD_LRS/U"},
+		},
+		"should find sizes allowing high throughput, including ultra disks": {
+			filter: DiskBandwidthAtLeast(900),
+			expect: []string{"Premium_LRS/P80", "UltraSSD_LRS/U"},
+		},
+		"should not match virtual machines": {
+			filter: DiskIOPSAtLeast(0),
+			expect: func() []string {
+				var names []string
+				for i := range data {
+					if data[i].IsResourceType(Disks) {
+						names = append(names, data[i].GetName()+"/"+derefString(data[i].Size))
+					}
+				}
+				return names
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, sku := range Filter(data, tc.filter) {
+				got = append(got, sku.GetName()+"/"+derefString(sku.Size))
+			}
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
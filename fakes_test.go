@@ -3,7 +3,11 @@ package skewer
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
 )
@@ -32,15 +36,58 @@ func newDataWrapper(path string) (*dataWrapper, error) {
 // fakeClient is close to the simplest fake client implementation usable
 // by the cache. It does not use pagination like Azure clients.
 type fakeClient struct {
-	skus []compute.ResourceSku
-	err  error
+	skus  []compute.ResourceSku
+	err   error
+	calls int32
+	// block, when non-nil, delays List until it is closed.
+	block chan struct{}
 }
 
 func (f *fakeClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
-	if f.err != nil {
-		return nil, f.err
+	atomic.AddInt32(&f.calls, 1)
+	if f.block != nil {
+		<-f.block
+	}
+	// Like iterate, return any skus collected alongside the error.
+	return f.skus, f.err
+}
+
+// callCount returns the number of times List has been invoked.
+func (f *fakeClient) callCount() int32 {
+	return atomic.LoadInt32(&f.calls)
+}
+
+// fakeLocationClient serves skus per location. It honors the filter
+// created by WithLocation and records the number of calls per location.
+type fakeLocationClient struct {
+	skus map[string][]compute.ResourceSku
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (f *fakeLocationClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+	location := strings.TrimSuffix(strings.TrimPrefix(filter, "location eq '"), "'")
+
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[location]++
+	f.mu.Unlock()
+
+	skus, ok := f.skus[location]
+	if !ok {
+		return nil, fmt.Errorf("unknown location '%s'", location)
 	}
-	return f.skus, nil
+	return skus, nil
+}
+
+// callCount returns the number of times List has been invoked for a location.
+func (f *fakeLocationClient) callCount(location string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[location]
 }
 
 // fakeResourceClient is a fake client for the real Azure types. It
@@ -58,7 +105,6 @@ func (f *fakeResourceClient) ListComplete(ctx context.Context, filter string) (c
 	return f.res, nil
 }
 
-// nolint:deadcode,unused
 func newFailingFakeResourceClient(reterr error) *fakeResourceClient {
 	return &fakeResourceClient{
 		res: compute.ResourceSkusResultIterator{},
@@ -97,7 +143,6 @@ func (f *fakeResourceProviderClient) List(ctx context.Context, filter string) (c
 	return f.res, nil
 }
 
-// nolint:deadcode,unused
 func newFailingFakeResourceProviderClient(reterr error) *fakeResourceProviderClient {
 	return &fakeResourceProviderClient{
 		res: compute.ResourceSkusResultPage{},
@@ -160,12 +205,6 @@ func chunk(skus []compute.ResourceSku, count int) [][]compute.ResourceSku {
 	return divided
 }
 
-// pageList is a utility type to help construct ResourceSkusResultIterators.
-type pageList struct {
-	cursor int
-	pages  []compute.ResourceSkusResult
-}
-
 func newPageList(skuLists [][]compute.ResourceSku) *pageList {
 	list := &pageList{}
 	for i := 0; i < len(skuLists); i++ {
@@ -176,12 +215,56 @@ func newPageList(skuLists [][]compute.ResourceSku) *pageList {
 	return list
 }
 
-// next underpins ResourceSkusResultIterator's NextWithDone() method.
-func (p *pageList) next(context.Context, compute.ResourceSkusResult) (compute.ResourceSkusResult, error) {
-	if p.cursor >= len(p.pages) {
-		return compute.ResourceSkusResult{}, nil
+// flakyPageList serves pages like pageList, but fails fetches of
+// selected pages a number of times first. It counts every fetch.
+type flakyPageList struct {
+	pageList
+	failures map[int]int
+	err      error
+	fetches  int
+}
+
+func (f *flakyPageList) next(ctx context.Context, result compute.ResourceSkusResult) (compute.ResourceSkusResult, error) {
+	f.fetches++
+	if f.failures[f.cursor] > 0 {
+		f.failures[f.cursor]--
+		return compute.ResourceSkusResult{}, f.err
+	}
+	return f.pageList.next(ctx, result)
+}
+
+// newFlakyFakeResourceClient returns a ResourceClient whose listing
+// fails failures[i] times when fetching page i. The first page is
+// fetched eagerly, so it cannot fail.
+func newFlakyFakeResourceClient(skuLists [][]compute.ResourceSku, failures map[int]int, err error) (*fakeResourceClient, *flakyPageList, error) {
+	pages := &flakyPageList{
+		pageList: *newPageList(skuLists),
+		failures: failures,
+		err:      err,
+	}
+	newPage := compute.NewResourceSkusResultPage(pages.next)
+	if err := newPage.NextWithContext(context.Background()); err != nil {
+		return nil, nil, err
+	}
+	return &fakeResourceClient{
+		res: compute.NewResourceSkusResultIterator(newPage),
+	}, pages, nil
+}
+
+// flakyResourceClient fails ListComplete a number of times before
+// delegating to the wrapped client.
+type flakyResourceClient struct {
+	ResourceClient
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyResourceClient) ListComplete(ctx context.Context, filter string) (compute.ResourceSkusResultIterator, error) {
+	f.calls++
+	if f.failures > 0 {
+		f.failures--
+		return compute.ResourceSkusResultIterator{}, f.err
 	}
-	old := p.cursor
-	p.cursor++
-	return p.pages[old], nil
+	return f.ResourceClient.ListComplete(ctx, filter)
 }
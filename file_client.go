@@ -0,0 +1,147 @@
+package skewer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/pkg/errors"
+)
+
+// locationFilterPattern matches the filter produced by WithLocation.
+var locationFilterPattern = regexp.MustCompile(`(?i)^\s*location\s+eq\s+'([^']*)'\s*$`)
+
+// ErrUnsupportedFilter will be returned when an offline client receives
+// a filter it cannot evaluate.
+type ErrUnsupportedFilter struct {
+	filter string
+}
+
+func (e *ErrUnsupportedFilter) Error() string {
+	return fmt.Sprintf("unsupported filter '%s', only \"location eq '<location>'\" may be evaluated offline", e.filter)
+}
+
+// parseLocationFilter returns the location from a filter created by
+// WithLocation. An empty filter returns an empty location.
+func parseLocationFilter(filter string) (string, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", nil
+	}
+	matches := locationFilterPattern.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", &ErrUnsupportedFilter{filter}
+	}
+	return matches[1], nil
+}
+
+// FileClient is a client for offline use which serves resource skus
+// from files. Each file must contain a Resource SKUs list response,
+// i.e. an object of the form {"value": [...]}. It may be passed to
+// WithClient.
+type FileClient struct {
+	skus []compute.ResourceSku
+}
+
+// NewFileClient reads resource sku list responses from the provided
+// paths. Directories are searched, non-recursively, for files with a
+// .json extension in lexical order.
+func NewFileClient(paths ...string) (*FileClient, error) {
+	client := &FileClient{}
+	for _, path := range paths {
+		files, err := expandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			skus, err := readSkuListResponse(file)
+			if err != nil {
+				return nil, err
+			}
+			client.skus = append(client.skus, skus...)
+		}
+	}
+	return client, nil
+}
+
+// List returns all skus matching the filter. Only the location filter
+// created by WithLocation is supported, which matches skus listing the
+// location case-insensitively, as Azure does.
+func (f *FileClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+	location, err := parseLocationFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if location == "" {
+		return f.skus, nil
+	}
+
+	var skus []compute.ResourceSku
+	for i := range f.skus {
+		if hasLocation(f.skus[i], location) {
+			skus = append(skus, f.skus[i])
+		}
+	}
+
+	return skus, nil
+}
+
+// hasLocation returns true when the sku lists the location.
+func hasLocation(sku compute.ResourceSku, location string) bool {
+	if sku.Locations == nil {
+		return false
+	}
+	for _, candidate := range *sku.Locations {
+		if strings.EqualFold(candidate, location) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPath returns path if it is a file, or the json files it
+// contains if it is a directory.
+func expandPath(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// readSkuListResponse parses a file containing a Resource SKUs list
+// response.
+func readSkuListResponse(path string) ([]compute.ResourceSku, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result compute.ResourceSkusResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse resource skus from '%s'", path)
+	}
+
+	if result.Value == nil {
+		return nil, nil
+	}
+
+	return *result.Value, nil
+}
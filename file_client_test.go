@@ -0,0 +1,137 @@
+package skewer
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_parseLocationFilter(t *testing.T) {
+	cases := map[string]struct {
+		filter   string
+		location string
+		err      bool
+	}{
+		"empty filter should return empty location": {},
+		"location filter should parse": {
+			filter:   "location eq 'eastus'",
+			location: "eastus",
+		},
+		"location filter should parse regardless of case and spacing": {
+			filter:   " Location  EQ 'eastus' ",
+			location: "eastus",
+		},
+		"other filters should fail": {
+			filter: "name eq 'Standard_D4s_v3'",
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			location, err := parseLocationFilter(tc.filter)
+			if tc.err != (err != nil) {
+				t.Errorf("expected error: %t, got: %v", tc.err, err)
+			}
+			if location != tc.location {
+				t.Errorf("expected location '%s', got '%s'", tc.location, location)
+			}
+		})
+	}
+}
+
+func Test_FileClient(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := NewFileClient("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should list all skus without filter", func(t *testing.T) {
+		skus, err := client.List(ctx, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skus) != 436 {
+			t.Errorf("expected 436 skus, got %d", len(skus))
+		}
+	})
+
+	t.Run("should filter by location", func(t *testing.T) {
+		skus, err := client.List(ctx, "location eq 'EastUS'")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skus) != 436 {
+			t.Errorf("expected 436 skus, got %d", len(skus))
+		}
+		skus, err = client.List(ctx, "location eq 'westus'")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skus) != 0 {
+			t.Errorf("expected no skus in westus, got %d", len(skus))
+		}
+	})
+
+	t.Run("should reject unsupported filters", func(t *testing.T) {
+		_, err := client.List(ctx, "name eq 'foo'")
+		errUnsupported := &ErrUnsupportedFilter{}
+		if !errors.As(err, &errUnsupported) {
+			t.Errorf("expected ErrUnsupportedFilter, got: %v", err)
+		}
+	})
+
+	t.Run("should back a cache", func(t *testing.T) {
+		cache, err := NewCache(ctx, WithClient(client), WithLocation("eastus"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+			t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+		}
+	})
+}
+
+func Test_NewFileClient_Directory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "skewer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.json":     `{"value": [{"name": "foo", "resourceType": "virtualMachines", "locations": ["eastus"]}]}`,
+		"b.json":     `{"value": [{"name": "bar", "resourceType": "virtualMachines", "locations": ["westus"]}]}`,
+		"ignore.txt": `not json`,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client, err := NewFileClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skus, err := client.List(context.Background(), "location eq 'westus'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skus) != 1 || *skus[0].Name != "bar" {
+		t.Errorf("expected only sku bar in westus, got %v", skus)
+	}
+
+	if _, err := NewFileClient(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("expected missing file to fail")
+	}
+	if _, err := NewFileClient(filepath.Join(dir, "ignore.txt")); err == nil {
+		t.Errorf("expected malformed file to fail")
+	}
+}
@@ -0,0 +1,216 @@
+package skewer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/pkg/errors"
+)
+
+// fixtureManifestFile names the file describing a recorded listing.
+const fixtureManifestFile = "manifest.json"
+
+// fixtureNamePattern matches runs of characters not allowed in fixture
+// directory names.
+var fixtureNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// fixtureManifest describes a recorded listing. Pages are stored next
+// to it as page-0000.json, page-0001.json, and so on, each holding one
+// Resource SKUs list response including its nextLink.
+type fixtureManifest struct {
+	Filter string `json:"filter"`
+	Pages  int    `json:"pages"`
+}
+
+// FixturePath returns the directory within dir where the listing for
+// filter is recorded, e.g. "location-eq-eastus" for the filter created
+// by WithLocation("eastus"), or "all" for an empty filter.
+func FixturePath(dir, filter string) string {
+	name := strings.Trim(fixtureNamePattern.ReplaceAllString(strings.ToLower(filter), "-"), "-")
+	if name == "" {
+		name = "all"
+	}
+	return filepath.Join(dir, name)
+}
+
+func fixturePagePath(dir string, page int) string {
+	return filepath.Join(dir, fmt.Sprintf("page-%04d.json", page))
+}
+
+// RecordingResourceClient is a ResourceClient decorator which records
+// every listing it serves to a fixture directory, for later use with
+// ReplayResourceClient. Pages are recorded as they are served, replacing
+// any earlier recording for the filter, and the manifest is written once
+// the last page is reached. Failures, including those of later pages,
+// are returned as the wrapped client returned them, so they may be
+// retried or resumed; a listing which is never completed has no
+// manifest and is not replayed.
+type RecordingResourceClient struct {
+	client ResourceClient
+	dir    string
+}
+
+// NewRecordingResourceClient wraps client, recording listings under dir.
+func NewRecordingResourceClient(client ResourceClient, dir string) *RecordingResourceClient {
+	return &RecordingResourceClient{
+		client: client,
+		dir:    dir,
+	}
+}
+
+// ListComplete lists skus with the wrapped client, returning an iterator
+// which records each page as it is served.
+func (r *RecordingResourceClient) ListComplete(ctx context.Context, filter string) (compute.ResourceSkusResultIterator, error) {
+	iter, err := r.client.ListComplete(ctx, filter)
+	if err != nil {
+		return compute.ResourceSkusResultIterator{}, err
+	}
+
+	recording := &fixtureRecording{
+		iter:   iter,
+		dir:    FixturePath(r.dir, filter),
+		filter: filter,
+	}
+	page := compute.NewResourceSkusResultPage(recording.next)
+	if err := page.NextWithContext(ctx); err != nil {
+		return compute.ResourceSkusResultIterator{}, err
+	}
+	return compute.NewResourceSkusResultIterator(page), nil
+}
+
+// fixtureRecording serves the pages of a wrapped iterator, writing each
+// to a fixture as it is served.
+type fixtureRecording struct {
+	iter   compute.ResourceSkusResultIterator
+	dir    string
+	filter string
+	// pages counts the pages served, and remaining the values of the
+	// last one the wrapped iterator has yet to step past.
+	pages     int
+	remaining int
+}
+
+// next underpins the NextWithContext method of the recording iterator.
+// It steps the wrapped iterator past the current page, fetching the next
+// one. A failed step is repeated by the next call, so iteration can
+// resume after the failure.
+func (r *fixtureRecording) next(ctx context.Context, _ compute.ResourceSkusResult) (compute.ResourceSkusResult, error) {
+	for r.remaining > 0 {
+		if err := r.iter.NextWithContext(ctx); err != nil {
+			return compute.ResourceSkusResult{}, err
+		}
+		r.remaining--
+	}
+
+	if !r.iter.NotDone() {
+		if err := r.finish(); err != nil {
+			return compute.ResourceSkusResult{}, errors.Wrap(err, "failed to record resource skus")
+		}
+		return compute.ResourceSkusResult{}, nil
+	}
+
+	page := r.iter.Response()
+	if err := r.record(page); err != nil {
+		return compute.ResourceSkusResult{}, errors.Wrap(err, "failed to record resource skus")
+	}
+	if page.Value != nil {
+		// The wrapped iterator fetches the next page after stepping past
+		// the last value of this one.
+		r.remaining = len(*page.Value)
+	}
+	r.pages++
+	return page, nil
+}
+
+// record writes a page, replacing any existing recording in dir when it
+// is the first.
+func (r *fixtureRecording) record(page compute.ResourceSkusResult) error {
+	if r.pages == 0 {
+		if err := r.replace(); err != nil {
+			return err
+		}
+	}
+	return writeJSON(fixturePagePath(r.dir, r.pages), page)
+}
+
+// finish writes the manifest once every page has been recorded. It is
+// written last, so an interrupted recording is never mistaken for a
+// complete one.
+func (r *fixtureRecording) finish() error {
+	if r.pages == 0 {
+		if err := r.replace(); err != nil {
+			return err
+		}
+	}
+	return writeJSON(filepath.Join(r.dir, fixtureManifestFile), fixtureManifest{
+		Filter: r.filter,
+		Pages:  r.pages,
+	})
+}
+
+// replace removes any existing recording in dir.
+func (r *fixtureRecording) replace() error {
+	if err := os.RemoveAll(r.dir); err != nil {
+		return err
+	}
+	return os.MkdirAll(r.dir, 0750)
+}
+
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func readJSON(path string, value interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// ReplayResourceClient is a ResourceClient which serves listings
+// recorded by RecordingResourceClient, with the same pagination.
+type ReplayResourceClient struct {
+	dir string
+}
+
+// NewReplayResourceClient serves listings recorded under dir.
+func NewReplayResourceClient(dir string) *ReplayResourceClient {
+	return &ReplayResourceClient{
+		dir: dir,
+	}
+}
+
+// ListComplete returns an iterator over the pages recorded for filter.
+func (r *ReplayResourceClient) ListComplete(ctx context.Context, filter string) (compute.ResourceSkusResultIterator, error) {
+	dir := FixturePath(r.dir, filter)
+
+	var manifest fixtureManifest
+	if err := readJSON(filepath.Join(dir, fixtureManifestFile), &manifest); err != nil {
+		return compute.ResourceSkusResultIterator{}, errors.Wrapf(err, "failed to read fixture for filter '%s'", filter)
+	}
+
+	if manifest.Filter != filter {
+		return compute.ResourceSkusResultIterator{}, errors.Errorf("fixture in '%s' was recorded for filter '%s', not '%s'", dir, manifest.Filter, filter)
+	}
+
+	pages := make([]compute.ResourceSkusResult, manifest.Pages)
+	for i := range pages {
+		if err := readJSON(fixturePagePath(dir, i), &pages[i]); err != nil {
+			return compute.ResourceSkusResultIterator{}, errors.Wrapf(err, "failed to read fixture page %d for filter '%s'", i, filter)
+		}
+	}
+
+	return newPageListIterator(ctx, pages)
+}
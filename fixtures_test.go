@@ -0,0 +1,225 @@
+package skewer
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+func Test_FixturePath(t *testing.T) {
+	cases := map[string]struct {
+		filter string
+		expect string
+	}{
+		"empty filter": {
+			expect: "all",
+		},
+		"location filter": {
+			filter: "location eq 'EastUS'",
+			expect: "location-eq-eastus",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if got := FixturePath("fixtures", tc.filter); got != filepath.Join("fixtures", tc.expect) {
+				t.Errorf("expected fixture path '%s', got '%s'", tc.expect, got)
+			}
+		})
+	}
+}
+
+func Test_RecordAndReplay(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "skewer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	filter := "location eq 'eastus'"
+
+	live, err := newSuccessfulFakeResourceClient(chunk(dataWrapper.Value, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := NewRecordingResourceClient(live, dir).ListComplete(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordedPages, err := drainPages(ctx, recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recordedPages) != 10 {
+		t.Errorf("expected recording client to serve 10 pages, got %d", len(recordedPages))
+	}
+
+	replay := NewReplayResourceClient(dir)
+	replayed, err := replay.ListComplete(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayedPages, err := drainPages(ctx, replayed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayedPages) != len(recordedPages) {
+		t.Errorf("expected %d replayed pages, got %d", len(recordedPages), len(replayedPages))
+	}
+
+	t.Run("should back a cache", func(t *testing.T) {
+		cache, err := NewCache(ctx, WithResourceClient(replay), WithLocation("eastus"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+			t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+		}
+	})
+
+	t.Run("should fail for unrecorded filters", func(t *testing.T) {
+		if _, err := replay.ListComplete(ctx, "location eq 'westus'"); err == nil {
+			t.Errorf("expected unrecorded filter to fail")
+		}
+	})
+
+	t.Run("should not record failed listings", func(t *testing.T) {
+		failing := NewRecordingResourceClient(newFailingFakeResourceClient(os.ErrClosed), dir)
+		if _, err := failing.ListComplete(ctx, "location eq 'westus'"); err == nil {
+			t.Fatalf("expected failing client to fail")
+		}
+		if _, err := os.Stat(FixturePath(dir, "location eq 'westus'")); !os.IsNotExist(err) {
+			t.Errorf("expected no fixture for failed listing, got: %v", err)
+		}
+	})
+
+	t.Run("pages should be readable by file client", func(t *testing.T) {
+		client, err := NewFileClient(FixturePath(dir, filter))
+		if err != nil {
+			t.Fatal(err)
+		}
+		skus, err := client.List(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skus) != len(dataWrapper.Value) {
+			t.Errorf("expected %d skus, got %d", len(dataWrapper.Value), len(skus))
+		}
+	})
+}
+
+// drainPages traverses an iterator one page at a time, returning each
+// page as served.
+func drainPages(ctx context.Context, iter compute.ResourceSkusResultIterator) ([]compute.ResourceSkusResult, error) {
+	var pages []compute.ResourceSkusResult
+	for iter.NotDone() {
+		page := iter.Response()
+		pages = append(pages, page)
+		// The iterator fetches the next page after stepping past the
+		// last value of the current one.
+		for range *page.Value {
+			if err := iter.NextWithContext(ctx); err != nil {
+				return pages, err
+			}
+		}
+	}
+	return pages, nil
+}
+
+func Test_RecordingResourceClient_failures(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	filter := "location eq 'eastus'"
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	pages := chunk(dataWrapper.Value, 10)
+
+	t.Run("should resume from a failed page", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "skewer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		live, fetches, err := newFlakyFakeResourceClient(pages, map[int]int{5: 2}, newFakeDetailedError(http.StatusTooManyRequests, "0"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		skus, err := iterate(ctx, filter, NewRecordingResourceClient(live, dir).ListComplete, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skus) != len(dataWrapper.Value) {
+			t.Errorf("expected %d skus, got %d", len(dataWrapper.Value), len(skus))
+		}
+		// 10 pages, 2 failures, and one final fetch to find the end.
+		if fetches.fetches != 13 {
+			t.Errorf("expected only the failed page to be fetched again, got %d fetches", fetches.fetches)
+		}
+
+		replayed, err := NewReplayResourceClient(dir).ListComplete(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		replayedPages, err := drainPages(ctx, replayed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(replayedPages) != len(pages) {
+			t.Errorf("expected %d recorded pages, got %d", len(pages), len(replayedPages))
+		}
+	})
+
+	t.Run("should fail at the failed page without completing the recording", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "skewer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		live, fetches, err := newFlakyFakeResourceClient(pages, map[int]int{5: 10}, newFakeDetailedError(http.StatusInternalServerError, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		skus, err := iterate(ctx, filter, NewRecordingResourceClient(live, dir).ListComplete, policy)
+		errList := &ErrListResourceSkus{}
+		if !errors.As(err, &errList) || errList.Stage != StageNextPage || errList.Page != 5 {
+			t.Fatalf("expected failure fetching page 5, got: %v", err)
+		}
+		if expected := len(pagesBefore(pages, 5)); len(skus) != expected {
+			t.Errorf("expected %d skus before the failed page, got %d", expected, len(skus))
+		}
+		// 5 pages, then 1 attempt and 3 retries of the failing page.
+		if fetches.fetches != 9 {
+			t.Errorf("expected 9 page fetches, got %d", fetches.fetches)
+		}
+		if _, err := NewReplayResourceClient(dir).ListComplete(ctx, filter); err == nil {
+			t.Errorf("expected an interrupted recording not to be replayed")
+		}
+	})
+}
+
+func pagesBefore(pages [][]compute.ResourceSku, page int) []compute.ResourceSku {
+	var skus []compute.ResourceSku
+	for i := 0; i < page; i++ {
+		skus = append(skus, pages[i]...)
+	}
+	return skus
+}
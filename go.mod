@@ -4,7 +4,7 @@ go 1.13
 
 require (
 	github.com/Azure/azure-sdk-for-go v46.0.0+incompatible
-	github.com/Azure/go-autorest/autorest v0.9.8 // indirect
+	github.com/Azure/go-autorest/autorest v0.9.8
 	github.com/Azure/go-autorest/autorest/to v0.3.0
 	github.com/Azure/go-autorest/autorest/validation v0.2.0 // indirect
 	github.com/google/go-cmp v0.5.1
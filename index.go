@@ -0,0 +1,137 @@
+package skewer
+
+import (
+	"context"
+	"strings"
+)
+
+// skuIndex provides constant time lookups over a snapshot of cached
+// data. It is built whenever data is loaded and, like the data, never
+// modified afterwards.
+type skuIndex struct {
+	data []SKU
+	// byName maps resource type and name, both lower-cased, to the
+	// position of the first matching sku.
+	byName map[string]int
+	// byType and byFamily map lower-cased resource types and families
+	// to the positions of matching skus, in data order.
+	byType   map[string][]int
+	byFamily map[string][]int
+}
+
+func newSKUIndex(data []SKU) *skuIndex {
+	idx := &skuIndex{
+		data:     data,
+		byName:   make(map[string]int, len(data)),
+		byType:   make(map[string][]int),
+		byFamily: make(map[string][]int),
+	}
+
+	for i := range data {
+		resourceType := strings.ToLower(data[i].GetResourceType())
+		key := nameKey(resourceType, data[i].GetName())
+		if _, ok := idx.byName[key]; !ok {
+			idx.byName[key] = i
+		}
+		idx.byType[resourceType] = append(idx.byType[resourceType], i)
+		if data[i].Family != nil {
+			family := strings.ToLower(*data[i].Family)
+			idx.byFamily[family] = append(idx.byFamily[family], i)
+		}
+	}
+
+	return idx
+}
+
+func nameKey(resourceType, name string) string {
+	return strings.ToLower(resourceType) + "/" + strings.ToLower(name)
+}
+
+// get returns the first sku with a resource type and name.
+func (idx *skuIndex) get(name, resourceType string) (*SKU, bool) {
+	i, ok := idx.byName[nameKey(resourceType, name)]
+	if !ok {
+		return nil, false
+	}
+	return &idx.data[i], true
+}
+
+// collect returns copies of the skus at positions which satisfy all
+// filters.
+func (idx *skuIndex) collect(positions []int, filters []FilterFn) []SKU {
+	if idx.data == nil {
+		return nil
+	}
+
+	result := make([]SKU, 0, len(positions))
+	for _, i := range positions {
+		if All(&idx.data[i], filters) {
+			result = append(result, idx.data[i])
+		}
+	}
+	return result
+}
+
+// indexed returns the index over the current data, building it if the
+// data was set without one.
+func (c *Cache) indexed() *skuIndex {
+	c.mu.RLock()
+	idx := c.index
+	c.mu.RUnlock()
+	if idx != nil {
+		return idx
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.index == nil {
+		c.index = newSKUIndex(c.data)
+	}
+	return c.index
+}
+
+// ListByResourceType returns the skus of a resource type which satisfy
+// all filters, using the resource type index.
+func (c *Cache) ListByResourceType(ctx context.Context, resourceType string, filters ...FilterFn) []SKU {
+	c.revalidate(ctx)
+	idx := c.indexed()
+	return idx.collect(idx.byType[strings.ToLower(resourceType)], filters)
+}
+
+// ListByFamily returns the skus of a family, such as
+// "standardDSv3Family", which satisfy all filters, using the family
+// index.
+func (c *Cache) ListByFamily(ctx context.Context, family string, filters ...FilterFn) []SKU {
+	c.revalidate(ctx)
+	idx := c.indexed()
+	return idx.collect(idx.byFamily[strings.ToLower(family)], filters)
+}
+
+// ListByCapabilities returns the skus of a resource type whose
+// capabilities satisfy all filters. Capabilities are parsed once when
+// data is loaded, so repeated queries avoid scanning and parsing them.
+func (c *Cache) ListByCapabilities(ctx context.Context, resourceType string, filters ...CapabilityFilterFn) []SKU {
+	c.revalidate(ctx)
+	idx := c.indexed()
+	positions := idx.byType[strings.ToLower(resourceType)]
+	if idx.data == nil {
+		return nil
+	}
+
+	result := make([]SKU, 0, len(positions))
+	for _, i := range positions {
+		if allCapabilities(Capabilities{idx.data[i].Capabilities}, filters) {
+			result = append(result, idx.data[i])
+		}
+	}
+	return result
+}
+
+func allCapabilities(capabilities Capabilities, filters []CapabilityFilterFn) bool {
+	for _, filter := range filters {
+		if !filter(capabilities) {
+			return false
+		}
+	}
+	return true
+}
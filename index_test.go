@@ -0,0 +1,180 @@
+package skewer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newFakeFamilySku(name, resourceType, family string) compute.ResourceSku {
+	return compute.ResourceSku{
+		Name:         to.StringPtr(name),
+		ResourceType: to.StringPtr(resourceType),
+		Family:       to.StringPtr(family),
+	}
+}
+
+func skuNames(skus []SKU) []string {
+	names := []string{}
+	for i := range skus {
+		names = append(names, skus[i].GetName())
+	}
+	return names
+}
+
+func Test_Cache_Index(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{
+		skus: []compute.ResourceSku{
+			newFakeFamilySku("Standard_D2s_v3", VirtualMachines, "standardDSv3Family"),
+			newFakeFamilySku("Standard_D4s_v3", VirtualMachines, "standardDSv3Family"),
+			newFakeFamilySku("Standard_E4s_v3", VirtualMachines, "standardESv3Family"),
+			newFakeFamilySku("Premium_LRS", Disks, "premium"),
+			newFakeFamilySku("standard_d2s_v3", VirtualMachines, "duplicate"),
+		},
+	}
+	cache, err := NewCache(ctx, WithClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		list     func() []SKU
+		expected []string
+	}{
+		"by resource type": {
+			list:     func() []SKU { return cache.ListByResourceType(ctx, "VIRTUALMACHINES") },
+			expected: []string{"Standard_D2s_v3", "Standard_D4s_v3", "Standard_E4s_v3", "standard_d2s_v3"},
+		},
+		"by resource type with filters": {
+			list:     func() []SKU { return cache.ListByResourceType(ctx, VirtualMachines, NameFilter("standard_e4s_v3")) },
+			expected: []string{"Standard_E4s_v3"},
+		},
+		"by family": {
+			list:     func() []SKU { return cache.ListByFamily(ctx, "standarddsv3family") },
+			expected: []string{"Standard_D2s_v3", "Standard_D4s_v3"},
+		},
+		"by unknown family": {
+			list:     func() []SKU { return cache.ListByFamily(ctx, "unknown") },
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, skuNames(tc.list())); diff != "" {
+				t.Errorf("expected skus to match: %s", diff)
+			}
+		})
+	}
+
+	t.Run("should return the first match", func(t *testing.T) {
+		sku, found := cache.Get(ctx, "STANDARD_D2S_V3", "virtualmachines")
+		if !found || sku.Family == nil || *sku.Family != "standardDSv3Family" {
+			t.Errorf("expected first Standard_D2s_v3, got %+v", sku)
+		}
+	})
+
+	t.Run("should rebuild on refresh", func(t *testing.T) {
+		client.skus = []compute.ResourceSku{newFakeFamilySku("Standard_NC6", VirtualMachines, "standardNCFamily")}
+		if _, err := cache.Refresh(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, found := cache.Get(ctx, "Standard_D2s_v3", VirtualMachines); found {
+			t.Errorf("expected Standard_D2s_v3 to be gone after refresh")
+		}
+		if _, found := cache.Get(ctx, "Standard_NC6", VirtualMachines); !found {
+			t.Errorf("expected to find Standard_NC6 after refresh")
+		}
+		if diff := cmp.Diff([]string{"Standard_NC6"}, skuNames(cache.ListByFamily(ctx, "standardNCFamily"))); diff != "" {
+			t.Errorf("expected family index to be rebuilt: %s", diff)
+		}
+	})
+}
+
+func newBenchmarkCache(b *testing.B) *Cache {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	cache, err := NewStaticCache(Wrap(dataWrapper.Value), WithLocation("eastus"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return cache
+}
+
+// BenchmarkFilterGet measures the linear scan Get used before indexing,
+// as a baseline for BenchmarkCache_Get.
+func BenchmarkFilterGet(b *testing.B) {
+	cache := newBenchmarkCache(b)
+	data := cache.skus()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		filtered := Filter(data, ResourceTypeFilter(VirtualMachines), NameFilter("standard_nc6"))
+		if len(filtered) < 1 {
+			b.Fatal("expected to find standard_nc6")
+		}
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	ctx := context.Background()
+	cache := newBenchmarkCache(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, found := cache.Get(ctx, "standard_nc6", VirtualMachines); !found {
+			b.Fatal("expected to find standard_nc6")
+		}
+	}
+}
+
+// BenchmarkFilterVirtualMachines is the baseline for
+// BenchmarkCache_GetVirtualMachines.
+func BenchmarkFilterVirtualMachines(b *testing.B) {
+	cache := newBenchmarkCache(b)
+	data := cache.skus()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Filter(data, ResourceTypeFilter(VirtualMachines))
+	}
+}
+
+func BenchmarkCache_GetVirtualMachines(b *testing.B) {
+	ctx := context.Background()
+	cache := newBenchmarkCache(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.GetVirtualMachines(ctx)
+	}
+}
+
+// BenchmarkFilterAvailabilityZonesForSize is the baseline for
+// BenchmarkCache_GetVirtualMachineAvailabilityZonesForSize.
+func BenchmarkFilterAvailabilityZonesForSize(b *testing.B) {
+	ctx := context.Background()
+	cache := newBenchmarkCache(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.GetAvailabilityZones(ctx, ResourceTypeFilter(VirtualMachines), NameFilter("standard_d4s_v3"))
+	}
+}
+
+func BenchmarkCache_GetVirtualMachineAvailabilityZonesForSize(b *testing.B) {
+	ctx := context.Background()
+	cache := newBenchmarkCache(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.GetVirtualMachineAvailabilityZonesForSize(ctx, "standard_d4s_v3")
+	}
+}
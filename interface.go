@@ -2,9 +2,9 @@ package skewer
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
-	"github.com/pkg/errors"
 )
 
 // ResourceClient is the required Azure client interface used to populate skewer's data.
@@ -19,7 +19,7 @@ type ResourceProviderClient interface {
 }
 
 // client defines the internal interface required by the skewer Cache.
-// TODO(ace): implement a lazy iterator with caching (and a cursor?)
+// See SKUIterator for lazy traversal of a ResourceClient.
 type client interface {
 	List(ctx context.Context, filter string) ([]compute.ResourceSku, error)
 }
@@ -28,15 +28,16 @@ type client interface {
 // signature to collect all resource skus from the iterator returned by ListComplete().
 type wrappedResourceClient struct {
 	client ResourceClient
+	retry  RetryPolicy
 }
 
 func newWrappedResourceClient(client ResourceClient) *wrappedResourceClient {
-	return &wrappedResourceClient{client}
+	return &wrappedResourceClient{client: client}
 }
 
 // List greedily traverses all returned sku pages
 func (w *wrappedResourceClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
-	return iterate(ctx, filter, w.client.ListComplete)
+	return iterate(ctx, filter, w.client.ListComplete, w.retry)
 }
 
 // wrappedResourceProviderClient defines a wrapper for the typical Azure client
@@ -53,27 +54,75 @@ func newWrappedResourceProviderClient(client ResourceProviderClient) *wrappedRes
 func (w *wrappedResourceProviderClient) ListComplete(ctx context.Context, filter string) (compute.ResourceSkusResultIterator, error) {
 	page, err := w.client.List(ctx, filter)
 	if err != nil {
-		return compute.ResourceSkusResultIterator{}, nil
+		return compute.ResourceSkusResultIterator{}, err
 	}
 	return compute.NewResourceSkusResultIterator(page), nil
 }
 
 type iterFunc func(context.Context, string) (compute.ResourceSkusResultIterator, error)
 
+// ListStage identifies the step of a sku listing which failed.
+type ListStage string
+
+const (
+	// StageList is the initial request, which returns the first page.
+	StageList ListStage = "list"
+	// StageNextPage is a request for any subsequent page.
+	StageNextPage ListStage = "next page"
+)
+
+// ErrListResourceSkus will be returned when listing resource skus
+// fails. It records which request failed and wraps the original error.
+type ErrListResourceSkus struct {
+	// Stage is the request which failed.
+	Stage ListStage
+	// Page is the zero-based index of the page which failed to load.
+	// Listings with Azure iterators only count pages holding skus: the
+	// iterators fetch past empty pages without returning them, so empty
+	// pages before the failed one are not counted.
+	Page int
+	// Err is the error returned by the Azure client.
+	Err error
+}
+
+func (e *ErrListResourceSkus) Error() string {
+	if e.Stage == StageList {
+		return fmt.Sprintf("could not list resource skus: %s", e.Err)
+	}
+	return fmt.Sprintf("could not iterate resource skus at page %d: %s", e.Page, e.Err)
+}
+
+// Unwrap returns the error returned by the Azure client.
+func (e *ErrListResourceSkus) Unwrap() error {
+	return e.Err
+}
+
 // iterate invokes fn to get an iterator, then drains it into an array.
-func iterate(ctx context.Context, filter string, fn iterFunc) ([]compute.ResourceSku, error) {
-	iter, err := fn(ctx, filter)
+// Transient failures are retried according to policy. A failed page is
+// retried in place, so earlier pages are not fetched again. On failure,
+// the skus from all pages before the failed one are returned alongside
+// an *ErrListResourceSkus.
+func iterate(ctx context.Context, filter string, fn iterFunc, policy RetryPolicy) ([]compute.ResourceSku, error) {
+	iter, err := newSKUIterator(ctx, filter, fn, policy)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not list resource skus")
+		return nil, err
 	}
 
 	var skus []compute.ResourceSku
 	for iter.NotDone() {
-		skus = append(skus, iter.Value())
+		skus = append(skus, compute.ResourceSku(iter.Value()))
 		if err := iter.NextWithContext(ctx); err != nil {
-			return skus, errors.Wrap(err, "could not iterate resource skus")
+			return skus, err
 		}
 	}
 
 	return skus, nil
 }
+
+// pageLength returns the number of values in a page.
+func pageLength(page compute.ResourceSkusResult) int {
+	if page.Value == nil {
+		return 0
+	}
+	return len(*page.Value)
+}
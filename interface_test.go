@@ -0,0 +1,99 @@
+package skewer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+func Test_ListErrors(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	cases := map[string]struct {
+		option     func() (CacheOption, error)
+		expectPage int
+		stage      ListStage
+		message    string
+	}{
+		"failing resource client": {
+			option: func() (CacheOption, error) {
+				return WithResourceClient(newFailingFakeResourceClient(boom)), nil
+			},
+			stage:   StageList,
+			message: "could not list resource skus: boom",
+		},
+		"failing resource provider client": {
+			option: func() (CacheOption, error) {
+				return WithResourceProviderClient(newFailingFakeResourceProviderClient(boom)), nil
+			},
+			stage:   StageList,
+			message: "could not list resource skus: boom",
+		},
+		"failing page": {
+			option: func() (CacheOption, error) {
+				client, _, err := newFlakyFakeResourceClient(chunk(dataWrapper.Value, 10), map[int]int{3: 1}, boom)
+				return WithResourceClient(client), err
+			},
+			expectPage: 3,
+			stage:      StageNextPage,
+			message:    "could not iterate resource skus at page 3: boom",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			option, err := tc.option()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cache, err := NewCache(ctx, option)
+			if cache != nil {
+				t.Errorf("expected no cache on failure")
+			}
+			errList := &ErrListResourceSkus{}
+			if !errors.As(err, &errList) {
+				t.Fatalf("expected ErrListResourceSkus, got: %v", err)
+			}
+			if errList.Stage != tc.stage || errList.Page != tc.expectPage {
+				t.Errorf("expected failure at stage '%s' page %d, got stage '%s' page %d", tc.stage, tc.expectPage, errList.Stage, errList.Page)
+			}
+			if !errors.Is(err, boom) {
+				t.Errorf("expected error to wrap original failure")
+			}
+			if err.Error() != tc.message {
+				t.Errorf("expected message '%s', got '%s'", tc.message, err.Error())
+			}
+		})
+	}
+}
+
+func Test_iterate_PartialPages(t *testing.T) {
+	skuLists := [][]compute.ResourceSku{
+		make([]compute.ResourceSku, 3),
+		make([]compute.ResourceSku, 2),
+		make([]compute.ResourceSku, 4),
+	}
+	client, _, err := newFlakyFakeResourceClient(skuLists, map[int]int{2: 1}, newFakeDetailedError(http.StatusBadRequest, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skus, err := iterate(context.Background(), "", client.ListComplete, RetryPolicy{})
+	errList := &ErrListResourceSkus{}
+	if !errors.As(err, &errList) || errList.Page != 2 {
+		t.Fatalf("expected failure at page 2, got: %v", err)
+	}
+	if len(skus) != 5 {
+		t.Errorf("expected skus from the first two pages, got %d", len(skus))
+	}
+}
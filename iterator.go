@@ -0,0 +1,192 @@
+package skewer
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+// SKUIterator lazily traverses the resource skus returned by a
+// ResourceClient. Pages are fetched only when iteration reaches them,
+// so callers looking for a single sku may stop early instead of
+// downloading every page for a location. It mirrors the iterators of
+// the Azure SDK. An SKUIterator is not safe for concurrent use.
+type SKUIterator struct {
+	iter   compute.ResourceSkusResultIterator
+	policy RetryPolicy
+
+	// page is the zero-based index of the current page, and remaining
+	// the number of values left on it, including the current one.
+	page      int
+	remaining int
+
+	// cache, when set, receives the fully traversed pages, collected
+	// since start by the load numbered generation. filled is the number
+	// of pages last stored in the cache.
+	cache      *Cache
+	start      time.Time
+	generation uint64
+	collected  []SKU
+	filled     int
+}
+
+// NewSKUIterator lists skus with client and returns an iterator
+// positioned at the first sku. Only the first page is fetched.
+func NewSKUIterator(ctx context.Context, client ResourceClient, filter string) (*SKUIterator, error) {
+	return newSKUIterator(ctx, filter, client.ListComplete, RetryPolicy{})
+}
+
+func newSKUIterator(ctx context.Context, filter string, fn iterFunc, policy RetryPolicy) (*SKUIterator, error) {
+	var iter compute.ResourceSkusResultIterator
+	err := policy.do(ctx, func() error {
+		var err error
+		iter, err = fn(ctx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, &ErrListResourceSkus{Stage: StageList, Err: err}
+	}
+
+	return &SKUIterator{
+		iter:      iter,
+		policy:    policy,
+		remaining: pageLength(iter.Response()),
+	}, nil
+}
+
+// Iterate returns an iterator over the skus for this cache's location,
+// using the cache's client and retry policy. As pages are traversed
+// their skus are added to the cache, which reports itself incomplete
+// until iteration finishes. Pages are added in batches which double in
+// size, after the first, second, fourth, eighth page and so on, as each
+// addition copies every sku collected so far. A cache holding a
+// complete listing is only replaced once iteration finishes, and pages
+// are dropped once the cache stores data from a refresh started after
+// the iteration. Iterate requires a cache created with
+// WithResourceClient or WithResourceProviderClient; use NewStaticCache
+// to create one without listing every sku up front.
+func (c *Cache) Iterate(ctx context.Context) (*SKUIterator, error) {
+	if c.client == nil {
+		return nil, &ErrClientNil{}
+	}
+
+	wrapped, ok := c.client.(*wrappedResourceClient)
+	if !ok {
+		return nil, &ErrClientNotIterable{}
+	}
+
+	generation := c.nextGeneration()
+	start := time.Now()
+	iter, err := newSKUIterator(ctx, c.filter, wrapped.client.ListComplete, wrapped.retry)
+	if err != nil {
+		return nil, err
+	}
+
+	iter.cache = c
+	iter.start = start
+	iter.generation = generation
+	if !iter.NotDone() {
+		iter.fill()
+	}
+
+	return iter, nil
+}
+
+// ErrClientNotIterable will be returned when a user attempts to iterate
+// a cache whose client does not return Azure iterators.
+type ErrClientNotIterable struct {
+}
+
+func (e *ErrClientNotIterable) Error() string {
+	return "cache iteration requires a client provided by WithResourceClient or WithResourceProviderClient"
+}
+
+// NotDone returns true while the iterator is positioned at a sku.
+func (it *SKUIterator) NotDone() bool {
+	return it.iter.NotDone()
+}
+
+// Value returns the current sku.
+func (it *SKUIterator) Value() SKU {
+	return SKU(it.iter.Value())
+}
+
+// Page returns the zero-based index of the page holding the current
+// sku, counted as for ErrListResourceSkus.
+func (it *SKUIterator) Page() int {
+	return it.page
+}
+
+// NextWithContext advances to the next sku, fetching the next page when
+// the current one is exhausted. Transient failures are retried
+// according to the retry policy, resuming from the failed page. On
+// failure the iterator keeps its position, and an *ErrListResourceSkus
+// is returned.
+func (it *SKUIterator) NextWithContext(ctx context.Context) error {
+	if !it.iter.NotDone() {
+		return nil
+	}
+
+	leaving := it.remaining == 1
+	page := it.iter.Response()
+
+	if err := it.policy.do(ctx, func() error { return it.iter.NextWithContext(ctx) }); err != nil {
+		return &ErrListResourceSkus{Stage: StageNextPage, Page: it.page + 1, Err: err}
+	}
+
+	it.remaining--
+	if leaving {
+		if it.cache != nil {
+			for i := range *page.Value {
+				it.collected = append(it.collected, SKU((*page.Value)[i]))
+			}
+		}
+		it.page, it.remaining = it.page+1, pageLength(it.iter.Response())
+		it.fill()
+	}
+
+	return nil
+}
+
+// Find advances the iterator until it is positioned at a sku matching
+// all filters, which is returned. It returns false when the listing is
+// exhausted without a match.
+func (it *SKUIterator) Find(ctx context.Context, filters ...FilterFn) (SKU, bool, error) {
+	for it.NotDone() {
+		sku := it.Value()
+		if All(&sku, filters) {
+			return sku, true, nil
+		}
+		if err := it.NextWithContext(ctx); err != nil {
+			return SKU{}, false, err
+		}
+	}
+	return SKU{}, false, nil
+}
+
+// fill publishes the skus collected so far to the cache, marking them
+// complete once iteration has finished. Until then, it only publishes
+// once the number of pages collected has doubled, so the cost of
+// publishing stays linear in the number of skus.
+func (it *SKUIterator) fill() {
+	if it.cache == nil {
+		return
+	}
+	if it.iter.NotDone() && it.page < 2*it.filled {
+		return
+	}
+	it.filled = it.page
+
+	data := make([]SKU, len(it.collected))
+	copy(data, it.collected)
+
+	if !it.iter.NotDone() {
+		it.cache.storeComplete(data, it.start, it.generation)
+		return
+	}
+
+	it.cache.storeIncomplete(data, Completeness{
+		MissingFromPage: it.page,
+	}, it.generation)
+}
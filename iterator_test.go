@@ -0,0 +1,181 @@
+package skewer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newFakeSkuPages returns count pages of size skus each, named
+// "sku-<page>-<index>".
+func newFakeSkuPages(count, size int) [][]compute.ResourceSku {
+	pages := make([][]compute.ResourceSku, count)
+	for i := range pages {
+		for j := 0; j < size; j++ {
+			pages[i] = append(pages[i], compute.ResourceSku{
+				Name:         to.StringPtr(fmt.Sprintf("sku-%d-%d", i, j)),
+				ResourceType: to.StringPtr(VirtualMachines),
+			})
+		}
+	}
+	return pages
+}
+
+func Test_SKUIterator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should traverse every sku", func(t *testing.T) {
+		client, err := newSuccessfulFakeResourceClient(newFakeSkuPages(4, 3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		iter, err := NewSKUIterator(ctx, client, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for iter.NotDone() {
+			sku := iter.Value()
+			if expected := fmt.Sprintf("sku-%d-%d", count/3, count%3); sku.GetName() != expected || iter.Page() != count/3 {
+				t.Errorf("expected sku %s on page %d, got %s on page %d", expected, count/3, sku.GetName(), iter.Page())
+			}
+			count++
+			if err := iter.NextWithContext(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if count != 12 {
+			t.Errorf("expected 12 skus, got %d", count)
+		}
+	})
+
+	t.Run("should stop fetching once found", func(t *testing.T) {
+		client, pages, err := newFlakyFakeResourceClient(newFakeSkuPages(10, 3), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iter, err := NewSKUIterator(ctx, client, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sku, found, err := iter.Find(ctx, NameFilter("SKU-2-1"))
+		if err != nil || !found {
+			t.Fatalf("expected to find sku-2-1, got found '%t' and error '%v'", found, err)
+		}
+		if sku.GetName() != "sku-2-1" {
+			t.Errorf("expected sku-2-1, got %s", sku.GetName())
+		}
+		if pages.fetches != 3 {
+			t.Errorf("expected 3 page fetches, got %d", pages.fetches)
+		}
+	})
+
+	t.Run("should report failed page", func(t *testing.T) {
+		boom := errors.New("boom")
+		client, _, err := newFlakyFakeResourceClient(newFakeSkuPages(3, 2), map[int]int{1: 1}, boom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iter, err := NewSKUIterator(ctx, client, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, found, err := iter.Find(ctx, NameFilter("missing"))
+		errList := &ErrListResourceSkus{}
+		if found || !errors.As(err, &errList) || errList.Page != 1 {
+			t.Fatalf("expected failure at page 1, got found '%t' and error '%v'", found, err)
+		}
+		// The iterator keeps its position, so iteration may resume.
+		if _, found, err := iter.Find(ctx, NameFilter("sku-2-1")); !found || err != nil {
+			t.Errorf("expected to resume and find sku-2-1, got found '%t' and error '%v'", found, err)
+		}
+	})
+}
+
+func Test_Cache_Iterate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should require an iterable client", func(t *testing.T) {
+		cache, err := NewStaticCache(nil, WithClient(&fakeClient{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		errNotIterable := &ErrClientNotIterable{}
+		if _, err := cache.Iterate(ctx); !errors.As(err, &errNotIterable) {
+			t.Errorf("expected ErrClientNotIterable, got: %v", err)
+		}
+	})
+
+	t.Run("should fill cache as it goes", func(t *testing.T) {
+		client, err := newSuccessfulFakeResourceClient(newFakeSkuPages(4, 3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache, err := NewStaticCache(nil, WithResourceClient(client), WithLocation("eastus"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		iter, err := cache.Iterate(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, found, err := iter.Find(ctx, NameFilter("sku-2-0")); !found || err != nil {
+			t.Fatalf("expected to find sku-2-0, got found '%t' and error '%v'", found, err)
+		}
+		if got := len(cache.List(ctx)); got != 6 {
+			t.Errorf("expected the first two pages in cache, got %d skus", got)
+		}
+		if completeness := cache.Completeness(); completeness.Complete || completeness.MissingFromPage != 2 {
+			t.Errorf("expected cache to be missing data from page 2, got %+v", completeness)
+		}
+
+		if _, found, err := iter.Find(ctx, NameFilter("missing")); found || err != nil {
+			t.Fatalf("expected to exhaust iterator, got found '%t' and error '%v'", found, err)
+		}
+		if got := len(cache.List(ctx)); got != 12 {
+			t.Errorf("expected every page in cache, got %d skus", got)
+		}
+		if completeness := cache.Completeness(); !completeness.Complete || cache.FetchedAt().IsZero() {
+			t.Errorf("expected complete cache after iteration, got %+v", completeness)
+		}
+	})
+	t.Run("should fill cache in doubling batches of pages", func(t *testing.T) {
+		client, err := newSuccessfulFakeResourceClient(newFakeSkuPages(7, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache, err := NewStaticCache(nil, WithResourceClient(client), WithLocation("eastus"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		iter, err := cache.Iterate(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Pages are added after the first, second and fourth, and the
+		// rest once iteration finishes.
+		var cached []int
+		for iter.NotDone() {
+			page := iter.Page()
+			if err := iter.NextWithContext(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if iter.Page() != page || !iter.NotDone() {
+				cached = append(cached, len(cache.List(ctx))/2)
+			}
+		}
+		if diff := cmp.Diff([]int{1, 2, 2, 4, 4, 4, 7}, cached); diff != "" {
+			t.Errorf("expected pages in cache after each page to match: %s", diff)
+		}
+		if completeness := cache.Completeness(); !completeness.Complete {
+			t.Errorf("expected complete cache after iteration, got %+v", completeness)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package skewer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// MultiLocationCache maintains one Cache per Azure location. Locations
+// are loaded lazily the first time they are queried, or eagerly with
+// Preload. All methods are safe for concurrent use.
+type MultiLocationCache struct {
+	opts []CacheOption
+	// interner, when set, is shared by the caches of every location, so
+	// values repeated across locations are stored once. Each cache
+	// counts the entries its data holds and releases them when the data
+	// is replaced, so the interner only holds values still cached by
+	// some location.
+	interner *interner
+
+	mu      sync.RWMutex
+	caches  map[string]*Cache
+	flights flightGroup
+}
+
+// NewMultiLocationCache creates an empty multi-location cache. The
+// provided options are applied to every per-location Cache, so they
+// must include exactly one client option and must not include
+// WithLocation.
+func NewMultiLocationCache(opts ...CacheOption) (*MultiLocationCache, error) {
+	probe := &Cache{}
+	for _, optionFn := range opts {
+		if err := optionFn(probe); err != nil {
+			return nil, err
+		}
+	}
+
+	if probe.client == nil {
+		return nil, &ErrClientNil{}
+	}
+
+	if probe.location != "" {
+		return nil, errors.Errorf("multi-location cache options must not set a location, got '%s'", probe.location)
+	}
+
+	return &MultiLocationCache{
+		opts:     opts,
+		interner: probe.interner,
+		caches:   make(map[string]*Cache),
+	}, nil
+}
+
+// Cache returns the Cache for a location, loading it on first use.
+// Concurrent loads of a location are coalesced as by Cache.Refresh.
+// Failed loads are not remembered, so the next call will try again.
+func (m *MultiLocationCache) Cache(ctx context.Context, location string) (*Cache, error) {
+	location = normalizeLocation(location)
+
+	m.mu.RLock()
+	cache, ok := m.caches[location]
+	m.mu.RUnlock()
+	if ok {
+		return cache, nil
+	}
+
+	value, _, err := m.flights.do(ctx, location, func(ctx context.Context) (interface{}, error) {
+		opts := append(append([]CacheOption{}, m.opts...), WithLocation(location))
+		if m.interner != nil {
+			opts = append(opts, m.shareInterner)
+		}
+		cache, err := NewCache(ctx, opts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load skus for location '%s'", location)
+		}
+
+		m.mu.Lock()
+		m.caches[location] = cache
+		m.mu.Unlock()
+
+		return cache, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*Cache), nil
+}
+
+// shareInterner is an option replacing the interner of a compact cache
+// with the one shared by all locations of m.
+func (m *MultiLocationCache) shareInterner(c *Cache) error {
+	c.interner = m.interner
+	return nil
+}
+
+// Preload concurrently loads every provided location which is not
+// already present. It returns the first error in the order of the
+// provided locations, after all loads finish.
+func (m *MultiLocationCache) Preload(ctx context.Context, locations ...string) error {
+	errs := make([]error, len(locations))
+
+	var wg sync.WaitGroup
+	for i := range locations {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = m.Cache(ctx, locations[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Locations returns the sorted list of locations which have been loaded.
+func (m *MultiLocationCache) Locations() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	locations := make([]string, 0, len(m.caches))
+	for location := range m.caches {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	return locations
+}
+
+// Get returns the first matching resource of a given name and type in a location.
+func (m *MultiLocationCache) Get(ctx context.Context, location, name, resourceType string) (SKU, bool, error) {
+	cache, err := m.Cache(ctx, location)
+	if err != nil {
+		return SKU{}, false, err
+	}
+	sku, found := cache.Get(ctx, name, resourceType)
+	return sku, found, nil
+}
+
+// List returns all resource types for a location.
+func (m *MultiLocationCache) List(ctx context.Context, location string, filters ...FilterFn) ([]SKU, error) {
+	cache, err := m.Cache(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return cache.List(ctx, filters...), nil
+}
+
+// GetVirtualMachines returns the list of all virtual machines *SKUs in a given azure location.
+func (m *MultiLocationCache) GetVirtualMachines(ctx context.Context, location string) ([]SKU, error) {
+	cache, err := m.Cache(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return cache.GetVirtualMachines(ctx), nil
+}
+
+// GetVirtualMachineAvailabilityZones returns all virtual machine zones available in a given location.
+func (m *MultiLocationCache) GetVirtualMachineAvailabilityZones(ctx context.Context, location string) ([]string, error) {
+	cache, err := m.Cache(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return cache.GetVirtualMachineAvailabilityZones(ctx), nil
+}
+
+// GetVirtualMachineAvailabilityZonesForSize returns all zones of a given location where a virtual machine size is available.
+func (m *MultiLocationCache) GetVirtualMachineAvailabilityZonesForSize(ctx context.Context, location, size string) ([]string, error) {
+	cache, err := m.Cache(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return cache.GetVirtualMachineAvailabilityZonesForSize(ctx, size), nil
+}
+
+// GetAvailabilityZones returns the list of all availability zones in a given azure location.
+func (m *MultiLocationCache) GetAvailabilityZones(ctx context.Context, location string, filters ...FilterFn) ([]string, error) {
+	cache, err := m.Cache(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return cache.GetAvailabilityZones(ctx, filters...), nil
+}
+
+// normalizeLocation maps equivalent spellings of a location to one key.
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.TrimSpace(location))
+}
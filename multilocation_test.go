@@ -0,0 +1,170 @@
+package skewer
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newFakeLocationSku(name, location string, zones ...string) compute.ResourceSku {
+	return compute.ResourceSku{
+		Name:         to.StringPtr(name),
+		ResourceType: to.StringPtr(VirtualMachines),
+		Locations:    &[]string{location},
+		LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			{
+				Location: to.StringPtr(location),
+				Zones:    &zones,
+			},
+		},
+	}
+}
+
+func Test_NewMultiLocationCache(t *testing.T) {
+	cases := map[string]struct {
+		options []CacheOption
+		wantErr bool
+	}{
+		"should fail without client": {
+			wantErr: true,
+		},
+		"should fail with location": {
+			options: []CacheOption{WithClient(&fakeClient{}), WithLocation("eastus")},
+			wantErr: true,
+		},
+		"should succeed with client": {
+			options: []CacheOption{WithClient(&fakeClient{})},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := NewMultiLocationCache(tc.options...)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_MultiLocationCache(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeLocationClient{
+		skus: map[string][]compute.ResourceSku{
+			"eastus": {
+				newFakeLocationSku("foo", "eastus", "1", "2", "3"),
+				newFakeLocationSku("bar", "eastus", "1"),
+			},
+			"westeurope": {
+				newFakeLocationSku("foo", "westeurope", "1", "2"),
+			},
+			"westus": {
+				newFakeLocationSku("baz", "westus"),
+			},
+		},
+	}
+
+	cache, err := NewMultiLocationCache(WithClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should load lazily on first query", func(t *testing.T) {
+		if len(cache.Locations()) != 0 {
+			t.Fatalf("expected no locations before first query, got %v", cache.Locations())
+		}
+		if _, found, err := cache.Get(ctx, "EastUS", "bar", VirtualMachines); !found || err != nil {
+			t.Errorf("expected to find bar in eastus, got found '%t' and error '%v'", found, err)
+		}
+		if _, found, err := cache.Get(ctx, "westeurope", "bar", VirtualMachines); found || err != nil {
+			t.Errorf("expected not to find bar in westeurope, got found '%t' and error '%v'", found, err)
+		}
+		if _, _, err := cache.Get(ctx, "eastus", "foo", VirtualMachines); err != nil {
+			t.Fatal(err)
+		}
+		if calls := client.callCount("eastus"); calls != 1 {
+			t.Errorf("expected eastus to load exactly once, got %d", calls)
+		}
+		if diff := cmp.Diff([]string{"eastus", "westeurope"}, cache.Locations()); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("should answer zone queries per location", func(t *testing.T) {
+		zones, err := cache.GetVirtualMachineAvailabilityZonesForSize(ctx, "westeurope", "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"1", "2"}, zones, cmpopts.SortSlices(func(a, b string) bool {
+			return a < b
+		})); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("should preload locations", func(t *testing.T) {
+		if err := cache.Preload(ctx, "westus", "eastus"); err != nil {
+			t.Fatal(err)
+		}
+		if calls := client.callCount("westus"); calls != 1 {
+			t.Errorf("expected westus to load exactly once, got %d", calls)
+		}
+		vms, err := cache.GetVirtualMachines(ctx, "westus")
+		if err != nil || len(vms) != 1 {
+			t.Errorf("expected one virtual machine in westus, got %d and error '%v'", len(vms), err)
+		}
+	})
+
+	t.Run("should not remember failed loads", func(t *testing.T) {
+		if err := cache.Preload(ctx, "nowhere"); err == nil {
+			t.Fatalf("expected unknown location to fail")
+		}
+		if _, err := cache.List(ctx, "nowhere"); err == nil {
+			t.Fatalf("expected unknown location to fail")
+		}
+		if calls := client.callCount("nowhere"); calls != 2 {
+			t.Errorf("expected failed location to be retried, got %d calls", calls)
+		}
+	})
+}
+
+func Test_MultiLocationCache_CacheHonorsCallerContext(t *testing.T) {
+	client := &contextClient{
+		skus:    []compute.ResourceSku{newFakeLocationSku("foo", "eastus")},
+		release: make(chan struct{}),
+	}
+	m, err := NewMultiLocationCache(WithClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := make(chan error, 1)
+	go func() {
+		_, err := m.Cache(context.Background(), "eastus")
+		loaded <- err
+	}()
+	for atomic.LoadInt32(&client.calls) < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.Cache(ctx, "eastus"); err != context.DeadlineExceeded {
+		t.Errorf("expected the caller's deadline error while the location loads, got %v", err)
+	}
+
+	close(client.release)
+	if err := <-loaded; err != nil {
+		t.Fatalf("expected the first caller to load the location, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"eastus"}, m.Locations()); diff != "" {
+		t.Error(diff)
+	}
+}
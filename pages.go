@@ -0,0 +1,34 @@
+package skewer
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+// pageList is a utility type to help construct ResourceSkusResultIterators.
+type pageList struct {
+	cursor int
+	pages  []compute.ResourceSkusResult
+}
+
+// next underpins ResourceSkusResultIterator's NextWithDone() method.
+func (p *pageList) next(context.Context, compute.ResourceSkusResult) (compute.ResourceSkusResult, error) {
+	if p.cursor >= len(p.pages) {
+		return compute.ResourceSkusResult{}, nil
+	}
+	old := p.cursor
+	p.cursor++
+	return p.pages[old], nil
+}
+
+// newPageListIterator returns an iterator over pages which have
+// already been fetched, preserving their pagination.
+func newPageListIterator(ctx context.Context, pages []compute.ResourceSkusResult) (compute.ResourceSkusResultIterator, error) {
+	list := &pageList{pages: pages}
+	page := compute.NewResourceSkusResultPage(list.next)
+	if err := page.NextWithContext(ctx); err != nil {
+		return compute.ResourceSkusResultIterator{}, err
+	}
+	return compute.NewResourceSkusResultIterator(page), nil
+}
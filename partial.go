@@ -0,0 +1,116 @@
+package skewer
+
+import (
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/pkg/errors"
+)
+
+// WithPartialResults is a functional option to keep the skus collected
+// before a listing was interrupted by a failed page, instead of
+// discarding them. Partial data only replaces missing or partial data:
+// a cache holding a complete listing keeps it. Use Completeness to
+// check whether a cache holds partial data.
+func WithPartialResults() CacheOption {
+	return func(c *Cache) error {
+		c.partialResults = true
+		return nil
+	}
+}
+
+// Completeness describes whether cached data covers a full listing.
+type Completeness struct {
+	// Complete is false when the cache holds data from an interrupted
+	// listing.
+	Complete bool
+	// MissingFromPage is the zero-based index of the first page which
+	// could not be loaded, counted as for ErrListResourceSkus. That page
+	// and every later page are missing. It is zero when Complete is
+	// true.
+	MissingFromPage int
+	// Err is the error which interrupted the listing, if known.
+	Err error
+}
+
+// Completeness reports whether the cache holds a full listing or partial
+// data kept with WithPartialResults.
+func (c *Cache) Completeness() Completeness {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.incomplete != nil {
+		return *c.incomplete
+	}
+	return Completeness{Complete: true}
+}
+
+// keepPartial stores the skus collected by a listing which failed
+// part way through, if the cache is configured to keep partial results
+// and does not hold a complete listing. It returns true when the data
+// was kept.
+func (c *Cache) keepPartial(data []compute.ResourceSku, err error, generation uint64) bool {
+	if !c.partialResults || len(data) == 0 {
+		return false
+	}
+
+	var errList *ErrListResourceSkus
+	if !errors.As(err, &errList) || errList.Stage != StageNextPage {
+		return false
+	}
+
+	return c.storeIncomplete(Wrap(data), Completeness{
+		MissingFromPage: errList.Page,
+		Err:             err,
+	}, generation)
+}
+
+// storeIncomplete replaces cached data with partial data from the load
+// numbered generation, unless the cache holds a complete listing or
+// data from a later load. It returns true when the data was stored.
+func (c *Cache) storeIncomplete(data []SKU, completeness Completeness, generation uint64) bool {
+	data, held := c.prepareData(data)
+
+	c.mu.Lock()
+	if (c.incomplete == nil && len(c.data) > 0) || generation < c.dataGeneration {
+		if c.interner != nil {
+			c.interner.release(held)
+		}
+		c.mu.Unlock()
+		return false
+	}
+
+	update := c.beginUpdate(c.data, data)
+	c.replaceData(data, held)
+	c.incomplete = &completeness
+	c.dataGeneration = generation
+	c.mu.Unlock()
+
+	c.publish(update)
+	return true
+}
+
+// storeComplete replaces cached data with a complete listing from the
+// load numbered generation, unless the cache holds data from a later
+// load. It returns true when the data was stored.
+func (c *Cache) storeComplete(data []SKU, fetchedAt time.Time, generation uint64) bool {
+	data, held := c.prepareData(data)
+
+	c.mu.Lock()
+	if generation < c.dataGeneration {
+		if c.interner != nil {
+			c.interner.release(held)
+		}
+		c.mu.Unlock()
+		return false
+	}
+
+	update := c.beginUpdate(c.data, data)
+	c.replaceData(data, held)
+	c.fetchedAt = fetchedAt
+	c.incomplete = nil
+	c.dataGeneration = generation
+	c.mu.Unlock()
+
+	c.publish(update)
+	return true
+}
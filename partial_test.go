@@ -0,0 +1,136 @@
+package skewer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+func Test_Cache_PartialResults(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	boom := errors.New("boom")
+	pages := chunk(dataWrapper.Value, 10)
+	partial := len(pages[0]) + len(pages[1]) + len(pages[2])
+
+	newInterruptedClient := func(t *testing.T) ResourceClient {
+		client, _, err := newFlakyFakeResourceClient(pages, map[int]int{3: 1}, boom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	t.Run("should discard partial data by default", func(t *testing.T) {
+		if _, err := NewCache(ctx, WithResourceClient(newInterruptedClient(t))); err == nil {
+			t.Errorf("expected interrupted listing to fail")
+		}
+	})
+
+	t.Run("should keep partial data when enabled", func(t *testing.T) {
+		cache, err := NewCache(ctx, WithResourceClient(newInterruptedClient(t)), WithPartialResults())
+		if err != nil {
+			t.Fatal(err)
+		}
+		completeness := cache.Completeness()
+		if completeness.Complete || completeness.MissingFromPage != 3 || !errors.Is(completeness.Err, boom) {
+			t.Errorf("expected incomplete data missing from page 3, got %+v", completeness)
+		}
+		if got := len(cache.List(ctx)); got != partial {
+			t.Errorf("expected %d skus from the first three pages, got %d", partial, got)
+		}
+		if !cache.FetchedAt().IsZero() {
+			t.Errorf("expected partial data not to count as fetched")
+		}
+
+		var buf bytes.Buffer
+		if err := cache.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := NewCacheFromSnapshot(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if completeness := loaded.Completeness(); completeness.Complete || completeness.MissingFromPage != 3 {
+			t.Errorf("expected snapshot to preserve incompleteness, got %+v", completeness)
+		}
+	})
+
+	t.Run("should complete on successful refresh", func(t *testing.T) {
+		client := &fakeClient{
+			skus: dataWrapper.Value[:partial],
+			err:  &ErrListResourceSkus{Stage: StageNextPage, Page: 3, Err: boom},
+		}
+		cache, err := NewCache(ctx, WithClient(client), WithPartialResults())
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.skus, client.err = dataWrapper.Value, nil
+		if _, err := cache.Refresh(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if completeness := cache.Completeness(); !completeness.Complete {
+			t.Errorf("expected complete data after refresh, got %+v", completeness)
+		}
+		if got := len(cache.List(ctx)); got != len(dataWrapper.Value) {
+			t.Errorf("expected %d skus, got %d", len(dataWrapper.Value), got)
+		}
+	})
+
+	t.Run("should not replace complete data", func(t *testing.T) {
+		client := &fakeClient{skus: dataWrapper.Value}
+		cache, err := NewCache(ctx, WithClient(client), WithPartialResults())
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.skus = dataWrapper.Value[:partial]
+		client.err = &ErrListResourceSkus{Stage: StageNextPage, Page: 3, Err: boom}
+		result, err := cache.Refresh(ctx)
+		if err == nil || result.Partial {
+			t.Errorf("expected refresh to fail without keeping partial data, got %+v", result)
+		}
+		if got := len(cache.List(ctx)); got != len(dataWrapper.Value) {
+			t.Errorf("expected complete data with %d skus to be kept, got %d", len(dataWrapper.Value), got)
+		}
+	})
+
+	t.Run("should not keep data of a refresh no caller waits for", func(t *testing.T) {
+		client := &fakeClient{
+			skus: dataWrapper.Value[:partial],
+			err:  &ErrListResourceSkus{Stage: StageNextPage, Page: 3, Err: boom},
+		}
+		cache, err := NewCache(ctx, WithClient(client), WithPartialResults())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The flight group cancels the refresh once every caller left.
+		client.skus = dataWrapper.Value[:partial+1]
+		client.err = &ErrListResourceSkus{Stage: StageNextPage, Page: 4, Err: context.Canceled}
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		result, err := cache.refresh(cancelled)
+		if err == nil || result.Partial {
+			t.Errorf("expected refresh to fail without keeping partial data, got %+v", result)
+		}
+		if completeness := cache.Completeness(); completeness.MissingFromPage != 3 {
+			t.Errorf("expected the earlier partial data to be kept, got %+v", completeness)
+		}
+	})
+
+	t.Run("should not keep data from failed first page", func(t *testing.T) {
+		client := &fakeClient{
+			skus: []compute.ResourceSku{},
+			err:  &ErrListResourceSkus{Stage: StageList, Err: boom},
+		}
+		if _, err := NewCache(ctx, WithClient(client), WithPartialResults()); err == nil {
+			t.Errorf("expected failed listing to fail")
+		}
+	})
+}
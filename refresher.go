@@ -0,0 +1,171 @@
+package skewer
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxRefreshBackoffFactor caps exponential backoff after consecutive
+// refresh failures at this multiple of the refresh interval.
+const maxRefreshBackoffFactor = 16
+
+// WithBackgroundRefresh is a functional option to configure a
+// background refresher for the cache. Once started with Start, the
+// cache refreshes itself every interval plus a random delay of up to
+// jitter, which spreads load when many processes share a schedule.
+// Consecutive failures back off exponentially, up to 16 times the
+// interval.
+func WithBackgroundRefresh(interval, jitter time.Duration) CacheOption {
+	return func(c *Cache) error {
+		if interval <= 0 {
+			return errors.Errorf("background refresh interval must be positive, got %s", interval)
+		}
+		if jitter < 0 {
+			return errors.Errorf("background refresh jitter must not be negative, got %s", jitter)
+		}
+		c.refreshInterval = interval
+		c.refreshJitter = jitter
+		return nil
+	}
+}
+
+// ErrRefresherNotConfigured will be returned when a user attempts to
+// start a background refresher without WithBackgroundRefresh.
+type ErrRefresherNotConfigured struct {
+}
+
+func (e *ErrRefresherNotConfigured) Error() string {
+	return "cache requires WithBackgroundRefresh to start a background refresher"
+}
+
+// ErrRefresherRunning will be returned when a user attempts to start a
+// background refresher twice.
+type ErrRefresherRunning struct {
+}
+
+func (e *ErrRefresherRunning) Error() string {
+	return "background refresher is already running"
+}
+
+// Start launches the background refresher configured by
+// WithBackgroundRefresh. It runs until ctx is cancelled or Stop is
+// called. The first refresh happens after one interval, since NewCache
+// already populates the cache.
+func (c *Cache) Start(ctx context.Context) error {
+	if c.client == nil {
+		return &ErrClientNil{}
+	}
+	if c.refreshInterval <= 0 {
+		return &ErrRefresherNotConfigured{}
+	}
+
+	c.refresherMu.Lock()
+	defer c.refresherMu.Unlock()
+
+	if c.refresherDone != nil {
+		select {
+		case <-c.refresherDone:
+			// previous refresher exited on context cancellation.
+		default:
+			return &ErrRefresherRunning{}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	c.refresherCancel = cancel
+	c.refresherDone = done
+	if c.refresherRand == nil {
+		c.refresherRand = newLockedRand()
+	}
+
+	go c.runRefresher(ctx, done, c.refresherRand)
+
+	return nil
+}
+
+// Stop halts the background refresher and waits for it to exit. It is
+// a no-op when no refresher is running.
+func (c *Cache) Stop() {
+	c.refresherMu.Lock()
+	defer c.refresherMu.Unlock()
+
+	if c.refresherDone == nil {
+		return
+	}
+
+	c.refresherCancel()
+	<-c.refresherDone
+	c.refresherCancel = nil
+	c.refresherDone = nil
+}
+
+func (c *Cache) runRefresher(ctx context.Context, done chan struct{}, rng *lockedRand) {
+	defer close(done)
+
+	failures := 0
+	for {
+		timer := time.NewTimer(nextRefreshDelay(c.refreshInterval, c.refreshJitter, failures, rng))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := c.Refresh(ctx); err != nil {
+			failures++
+		} else {
+			failures = 0
+		}
+	}
+}
+
+// lockedRand is a source of jitter for one refresher. The global
+// source of math/rand is seeded identically in every process before Go
+// 1.20, which would keep refreshes of many processes in step.
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newLockedRand() *lockedRand {
+	return &lockedRand{rand: rand.New(rand.NewSource(time.Now().UnixNano()))} // nolint:gosec // jitter does not need a secure source
+}
+
+func (r *lockedRand) int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Int63n(n)
+}
+
+// nextRefreshDelay computes the delay before the next refresh attempt,
+// doubling the interval for each consecutive failure and adding up to
+// jitter of random delay from rng. Delays too long to represent are
+// clamped rather than overflowing.
+func nextRefreshDelay(interval, jitter time.Duration, failures int, rng *lockedRand) time.Duration {
+	maxDelay := time.Duration(math.MaxInt64)
+	if interval <= maxDelay/maxRefreshBackoffFactor {
+		maxDelay = interval * maxRefreshBackoffFactor
+	}
+	delay := interval
+	for i := 0; i < failures && delay < maxDelay; i++ {
+		if delay > maxDelay/2 {
+			delay = maxDelay
+			break
+		}
+		delay *= 2
+	}
+	if jitter > 0 {
+		delay += time.Duration(rng.int63n(int64(jitter)))
+		if delay < 0 {
+			delay = math.MaxInt64
+		}
+	}
+	return delay
+}
@@ -0,0 +1,163 @@
+package skewer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func Test_nextRefreshDelay(t *testing.T) {
+	cases := map[string]struct {
+		interval time.Duration
+		failures int
+		expect   time.Duration
+	}{
+		"no failures should use interval": {
+			interval: time.Second,
+			failures: 0,
+			expect:   time.Second,
+		},
+		"one failure should double interval": {
+			interval: time.Second,
+			failures: 1,
+			expect:   2 * time.Second,
+		},
+		"three failures should multiply interval by eight": {
+			interval: time.Second,
+			failures: 3,
+			expect:   8 * time.Second,
+		},
+		"many failures should cap backoff": {
+			interval: time.Second,
+			failures: 100,
+			expect:   maxRefreshBackoffFactor * time.Second,
+		},
+		"long intervals should not overflow": {
+			interval: math.MaxInt64 / 4,
+			failures: 100,
+			expect:   math.MaxInt64,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if got := nextRefreshDelay(tc.interval, 0, tc.failures, newLockedRand()); got != tc.expect {
+				t.Errorf("expected delay %s, got %s", tc.expect, got)
+			}
+		})
+	}
+
+	t.Run("jitter should stay within bounds", func(t *testing.T) {
+		rng := newLockedRand()
+		for i := 0; i < 100; i++ {
+			got := nextRefreshDelay(time.Second, time.Second, 0, rng)
+			if got < time.Second || got >= 2*time.Second {
+				t.Fatalf("expected delay in [1s, 2s), got %s", got)
+			}
+		}
+	})
+
+	t.Run("jitter should not overflow", func(t *testing.T) {
+		if got := nextRefreshDelay(math.MaxInt64/16, math.MaxInt64/2, 100, newLockedRand()); got < math.MaxInt64/16 {
+			t.Errorf("expected the delay to be clamped, got %s", got)
+		}
+	})
+}
+
+func Test_Cache_Start(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("foo"),
+			ResourceType: to.StringPtr(VirtualMachines),
+		},
+	}
+
+	t.Run("should fail without configuration", func(t *testing.T) {
+		cache, err := NewCache(context.Background(), WithClient(&fakeClient{skus: skus}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		errNotConfigured := &ErrRefresherNotConfigured{}
+		if err := cache.Start(context.Background()); !errors.As(err, &errNotConfigured) {
+			t.Errorf("expected ErrRefresherNotConfigured, got: %v", err)
+		}
+	})
+
+	t.Run("should reject invalid interval", func(t *testing.T) {
+		if _, err := NewCache(context.Background(), WithClient(&fakeClient{skus: skus}), WithBackgroundRefresh(0, 0)); err == nil {
+			t.Errorf("expected zero interval to fail")
+		}
+	})
+
+	t.Run("should refresh until stopped", func(t *testing.T) {
+		client := &fakeClient{skus: skus}
+		cache, err := NewCache(context.Background(), WithClient(client), WithBackgroundRefresh(time.Millisecond, time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		errRunning := &ErrRefresherRunning{}
+		if err := cache.Start(context.Background()); !errors.As(err, &errRunning) {
+			t.Errorf("expected ErrRefresherRunning, got: %v", err)
+		}
+		waitForCalls(t, client, 3)
+		cache.Stop()
+
+		stopped := client.callCount()
+		time.Sleep(20 * time.Millisecond)
+		if calls := client.callCount(); calls != stopped {
+			t.Errorf("expected no refreshes after stop, got %d more", calls-stopped)
+		}
+
+		// Stop must be idempotent and the refresher restartable.
+		cache.Stop()
+		if err := cache.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		cache.Stop()
+	})
+
+	t.Run("should exit on context cancellation", func(t *testing.T) {
+		client := &fakeClient{skus: skus, err: errors.New("boom")}
+		cache, err := NewStaticCache(nil, WithClient(client), WithBackgroundRefresh(time.Millisecond, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := cache.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		waitForCalls(t, client, 1)
+		cancel()
+
+		select {
+		case <-cache.refresherDone:
+		case <-time.After(time.Second):
+			t.Fatalf("expected refresher to exit after context cancellation")
+		}
+
+		if err := cache.Start(context.Background()); err != nil {
+			t.Errorf("expected restart after cancellation to succeed, got: %v", err)
+		}
+		cache.Stop()
+	})
+}
+
+func waitForCalls(t *testing.T, client *fakeClient, calls int32) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for client.callCount() < calls {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at least %d calls, got %d", calls, client.callCount())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,174 @@
+package skewer
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy configures retries of transient failures while listing
+// skus with a ResourceClient or ResourceProviderClient. Throttling
+// (429), request timeouts (408) and transient server errors (500, 502,
+// 503 and 504) are retried. Each request is retried independently: a
+// failed page is retried without starting the listing over.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries of a single request.
+	// Zero disables retries.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry. It doubles for
+	// every subsequent retry of the same request, and a random jitter
+	// of up to half the delay is subtracted.
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential backoff, and delays requested by
+	// the server with Retry-After.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns a policy suitable for the Resource SKUs
+// API: up to 5 retries per request, starting at 1s and capped at 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 5,
+		BaseDelay:  time.Second,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+// WithRetryPolicy is a functional option to retry transient failures
+// while listing skus. It applies to caches backed by WithResourceClient
+// or WithResourceProviderClient.
+func WithRetryPolicy(policy RetryPolicy) CacheOption {
+	return func(c *Cache) error {
+		if policy.MaxRetries < 0 || policy.BaseDelay < 0 || policy.MaxDelay < 0 {
+			return errors.Errorf("retry policy values must not be negative, got %+v", policy)
+		}
+		c.retry = &policy
+		return nil
+	}
+}
+
+// do invokes fn until it succeeds, fails with an error which should not
+// be retried, exhausts the retry budget, or ctx is done. It returns the
+// last error from fn.
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxRetries || !isRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.delay(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns the time to wait before the retry following attempt,
+// preferring a delay requested by the server.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	if retryAfter, ok := retryAfter(err); ok {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return retryAfter
+	}
+
+	backoff := p.BaseDelay
+	for i := 0; i < attempt && (p.MaxDelay <= 0 || backoff < p.MaxDelay); i++ {
+		backoff *= 2
+	}
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	if half := int64(backoff / 2); half > 0 {
+		backoff -= time.Duration(rand.Int63n(half)) // nolint:gosec // jitter does not need a secure source
+	}
+
+	return backoff
+}
+
+// detailedError extracts the autorest error carrying the http response
+// of a failed request, if any.
+func detailedError(err error) (autorest.DetailedError, bool) {
+	var detailed autorest.DetailedError
+	if errors.As(err, &detailed) {
+		return detailed, true
+	}
+	var detailedPtr *autorest.DetailedError
+	if errors.As(err, &detailedPtr) && detailedPtr != nil {
+		return *detailedPtr, true
+	}
+	return autorest.DetailedError{}, false
+}
+
+// statusCode returns the http status code of a failed request, or zero.
+func statusCode(err error) int {
+	detailed, ok := detailedError(err)
+	if !ok {
+		return 0
+	}
+	if code, ok := detailed.StatusCode.(int); ok {
+		return code
+	}
+	if detailed.Response != nil {
+		return detailed.Response.StatusCode
+	}
+	return 0
+}
+
+// isRetryable returns true for throttling, timeouts and transient
+// server errors. Server errors such as 501 Not Implemented will not
+// succeed on retry.
+func isRetryable(err error) bool {
+	switch statusCode(err) {
+	case http.StatusTooManyRequests,
+		http.StatusRequestTimeout,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by the Retry-After header of a
+// failed request, which may be a number of seconds or an http date.
+func retryAfter(err error) (time.Duration, bool) {
+	detailed, ok := detailedError(err)
+	if !ok || detailed.Response == nil {
+		return 0, false
+	}
+
+	header := detailed.Response.Header.Get(autorest.HeaderRetryAfter)
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(header, 10, 64); err == nil && seconds >= 0 {
+		if seconds > int64(math.MaxInt64/time.Second) {
+			return math.MaxInt64, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
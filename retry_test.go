@@ -0,0 +1,200 @@
+package skewer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func newFakeDetailedError(code int, retryAfter string) error {
+	resp := &http.Response{
+		StatusCode: code,
+		Header:     http.Header{},
+	}
+	if retryAfter != "" {
+		resp.Header.Set(autorest.HeaderRetryAfter, retryAfter)
+	}
+	return autorest.NewErrorWithError(errors.New(http.StatusText(code)), "compute.ResourceSkusClient", "listNextResults", resp, "Failure sending next results request")
+}
+
+func Test_isRetryable(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		expect bool
+	}{
+		"plain error should not retry": {
+			err: errors.New("boom"),
+		},
+		"not found should not retry": {
+			err: newFakeDetailedError(http.StatusNotFound, ""),
+		},
+		"throttling should retry": {
+			err:    newFakeDetailedError(http.StatusTooManyRequests, ""),
+			expect: true,
+		},
+		"server error should retry": {
+			err:    newFakeDetailedError(http.StatusServiceUnavailable, ""),
+			expect: true,
+		},
+		"gateway timeout should retry": {
+			err:    newFakeDetailedError(http.StatusGatewayTimeout, ""),
+			expect: true,
+		},
+		"not implemented should not retry": {
+			err: newFakeDetailedError(http.StatusNotImplemented, ""),
+		},
+		"unsupported http version should not retry": {
+			err: newFakeDetailedError(http.StatusHTTPVersionNotSupported, ""),
+		},
+		"pointer to detailed error should retry": {
+			err: func() error {
+				err := newFakeDetailedError(http.StatusInternalServerError, "").(autorest.DetailedError)
+				return &err
+			}(),
+			expect: true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if got := isRetryable(tc.err); got != tc.expect {
+				t.Errorf("expected retryable %t, got %t", tc.expect, got)
+			}
+		})
+	}
+}
+
+func Test_RetryPolicy_delay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: 8 * time.Second}
+
+	t.Run("should honor retry-after seconds", func(t *testing.T) {
+		if got := policy.delay(0, newFakeDetailedError(http.StatusTooManyRequests, "5")); got != 5*time.Second {
+			t.Errorf("expected 5s delay, got %s", got)
+		}
+	})
+
+	t.Run("should cap retry-after at max delay", func(t *testing.T) {
+		for _, header := range []string{"17", "99999999999999999"} {
+			if got := policy.delay(0, newFakeDetailedError(http.StatusTooManyRequests, header)); got != policy.MaxDelay {
+				t.Errorf("expected retry-after %s to be capped at %s, got %s", header, policy.MaxDelay, got)
+			}
+		}
+	})
+
+	t.Run("should honor retry-after date", func(t *testing.T) {
+		uncapped := RetryPolicy{MaxRetries: 10, BaseDelay: time.Second}
+		date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+		got := uncapped.delay(0, newFakeDetailedError(http.StatusTooManyRequests, date))
+		if got <= 50*time.Second || got > time.Minute {
+			t.Errorf("expected about one minute delay, got %s", got)
+		}
+	})
+
+	t.Run("should back off exponentially with jitter", func(t *testing.T) {
+		err := newFakeDetailedError(http.StatusServiceUnavailable, "")
+		for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+			got := policy.delay(attempt, err)
+			if got <= max/2 || got > max {
+				t.Errorf("expected attempt %d delay in (%s, %s], got %s", attempt, max/2, max, got)
+			}
+		}
+	})
+}
+
+func Test_iterate_Retry(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	t.Run("should resume from failed page", func(t *testing.T) {
+		client, pages, err := newFlakyFakeResourceClient(chunk(dataWrapper.Value, 10), map[int]int{5: 2}, newFakeDetailedError(http.StatusTooManyRequests, "0"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		skus, err := iterate(ctx, "", client.ListComplete, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(skus) != len(dataWrapper.Value) {
+			t.Errorf("expected %d skus, got %d", len(dataWrapper.Value), len(skus))
+		}
+		// 10 pages, 2 failures, and one final fetch to find the end.
+		if pages.fetches != 13 {
+			t.Errorf("expected 13 page fetches, got %d", pages.fetches)
+		}
+	})
+
+	t.Run("should give up after max retries", func(t *testing.T) {
+		client, pages, err := newFlakyFakeResourceClient(chunk(dataWrapper.Value, 10), map[int]int{5: 10}, newFakeDetailedError(http.StatusInternalServerError, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := iterate(ctx, "", client.ListComplete, policy); err == nil {
+			t.Fatalf("expected iteration to fail")
+		}
+		// 5 pages, then 1 attempt and 3 retries of the failing page.
+		if pages.fetches != 9 {
+			t.Errorf("expected 9 page fetches, got %d", pages.fetches)
+		}
+	})
+
+	t.Run("should not retry permanent failures", func(t *testing.T) {
+		client, pages, err := newFlakyFakeResourceClient(chunk(dataWrapper.Value, 10), map[int]int{5: 1}, newFakeDetailedError(http.StatusForbidden, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := iterate(ctx, "", client.ListComplete, policy); err == nil {
+			t.Fatalf("expected iteration to fail")
+		}
+		if pages.fetches != 6 {
+			t.Errorf("expected 6 page fetches, got %d", pages.fetches)
+		}
+	})
+
+	t.Run("should retry listing through cache option", func(t *testing.T) {
+		inner, err := newSuccessfulFakeResourceClient([][]compute.ResourceSku{dataWrapper.Value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &flakyResourceClient{
+			ResourceClient: inner,
+			failures:       2,
+			err:            newFakeDetailedError(http.StatusTooManyRequests, ""),
+		}
+		cache, err := NewCache(ctx, WithRetryPolicy(policy), WithResourceClient(client), WithLocation("eastus"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.calls != 3 {
+			t.Errorf("expected 3 calls to list, got %d", client.calls)
+		}
+		if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+			t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+		}
+	})
+
+	t.Run("should not retry without policy", func(t *testing.T) {
+		inner, err := newSuccessfulFakeResourceClient([][]compute.ResourceSku{dataWrapper.Value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &flakyResourceClient{
+			ResourceClient: inner,
+			failures:       1,
+			err:            newFakeDetailedError(http.StatusTooManyRequests, ""),
+		}
+		if _, err := NewCache(ctx, WithResourceClient(client)); err == nil {
+			t.Errorf("expected listing to fail without retries")
+		}
+	})
+}
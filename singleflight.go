@@ -0,0 +1,151 @@
+package skewer
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// flightGroup deduplicates concurrent calls which share a key, so that
+// only one runs at a time and every caller receives its result. It is a
+// minimal version of golang.org/x/sync/singleflight, which also lets
+// each caller stop waiting when its own context is done.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a single in-progress or completed call.
+type flight struct {
+	done chan struct{}
+	// waiters counts the callers still waiting for the call, which is
+	// cancelled once none remain.
+	waiters int
+	dups    int
+	cancel  context.CancelFunc
+	value   interface{}
+	err     error
+	// panicked holds the value the call panicked with, if it did.
+	panicked interface{}
+}
+
+// do executes fn unless a call with the same key is already in flight,
+// in which case it waits for and returns the in-flight result. The
+// returned bool reports whether the result was delivered to more than
+// one caller.
+//
+// fn runs on a context carrying the values of the first caller's ctx,
+// but not its deadline or cancellation, so one caller giving up does
+// not fail the call for the others. Each caller returns its own ctx
+// error when ctx is done first, and fn's context is cancelled once
+// every caller has returned. If fn panics, every caller panics with the
+// same value; if no caller is left, the panic is raised again where fn
+// ran, with the stack at which it panicked.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, bool, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = make(map[string]*flight)
+	}
+	f, ok := g.flights[key]
+	if ok {
+		f.dups++
+		f.waiters++
+	} else {
+		callCtx, cancel := context.WithCancel(detachedContext{ctx})
+		f = &flight{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.flights[key] = f
+		go g.call(callCtx, key, f, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.panicked != nil {
+			panic(f.panicked)
+		}
+		g.mu.Lock()
+		shared := f.dups > 0
+		g.mu.Unlock()
+		return f.value, shared, f.err
+	case <-ctx.Done():
+		g.leave(key, f)
+		return nil, false, ctx.Err()
+	}
+}
+
+// call runs fn for a flight, always completing the flight so waiters
+// are released even if fn panics.
+func (g *flightGroup) call(ctx context.Context, key string, f *flight, fn func(context.Context) (interface{}, error)) {
+	defer func() {
+		r := recover()
+		var stack []byte
+		if r != nil {
+			f.panicked = r
+			stack = debug.Stack()
+		}
+		g.mu.Lock()
+		if g.flights[key] == f {
+			delete(g.flights, key)
+		}
+		// Callers only join flights in g.flights, so a flight which was
+		// left by every caller gains no more.
+		orphaned := f.waiters == 0
+		g.mu.Unlock()
+		f.cancel()
+		close(f.done)
+		if r != nil && orphaned {
+			panic(&flightPanic{value: r, stack: stack})
+		}
+	}()
+
+	f.value, f.err = fn(ctx)
+}
+
+// leave records that a caller stopped waiting for a flight. Once no
+// caller waits, the call is cancelled and later callers start a new one.
+func (g *flightGroup) leave(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+	f.cancel()
+}
+
+// waiting returns the number of callers waiting on an in-flight call
+// for key, excluding the caller which started it.
+func (g *flightGroup) waiting(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f, ok := g.flights[key]; ok {
+		return f.waiters - 1
+	}
+	return 0
+}
+
+// flightPanic is raised when a call panics after every caller stopped
+// waiting for it, so the panic is neither lost nor stripped of its stack.
+type flightPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p *flightPanic) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+// detachedContext carries the values of a context, but not its deadline
+// or cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
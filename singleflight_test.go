@@ -0,0 +1,260 @@
+package skewer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func Test_Cache_RefreshCoalesces(t *testing.T) {
+	skus := []compute.ResourceSku{
+		{
+			Name:         to.StringPtr("foo"),
+			ResourceType: to.StringPtr(VirtualMachines),
+		},
+	}
+
+	client := &fakeClient{skus: skus}
+	cache, err := NewCache(context.Background(), WithClient(client), WithLocation("eastus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.block = make(chan struct{})
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan RefreshResult, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := cache.Refresh(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+			results <- result
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for cache.flights.waiting(refreshFlight) < callers-1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d callers to wait on one refresh, got %d", callers-1, cache.flights.waiting(refreshFlight))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(client.block)
+	wg.Wait()
+	close(results)
+
+	if calls := client.callCount(); calls != 2 {
+		t.Errorf("expected one list call for creation and one for all concurrent refreshes, got %d", calls)
+	}
+	for result := range results {
+		if result.Count != len(skus) {
+			t.Errorf("expected every caller to observe %d skus, got %d", len(skus), result.Count)
+		}
+	}
+
+	// Completed flights must not be reused.
+	if _, err := cache.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls := client.callCount(); calls != 3 {
+		t.Errorf("expected a new list call after the previous refresh completed, got %d", calls)
+	}
+}
+
+// contextClient blocks List until release is closed or ctx is done.
+type contextClient struct {
+	skus    []compute.ResourceSku
+	release chan struct{}
+	calls   int32
+}
+
+func (f *contextClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+	atomic.AddInt32(&f.calls, 1)
+	select {
+	case <-f.release:
+		return f.skus, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func Test_Cache_RefreshHonorsCallerContext(t *testing.T) {
+	skus := []compute.ResourceSku{{Name: to.StringPtr("foo"), ResourceType: to.StringPtr(VirtualMachines)}}
+	client := &contextClient{skus: skus, release: make(chan struct{})}
+	close(client.release)
+	cache, err := NewCache(context.Background(), WithClient(client), WithLocation("eastus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.release = make(chan struct{})
+
+	// The first caller gives up before the refresh completes.
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cache.Refresh(leaderCtx)
+		leaderErr <- err
+	}()
+	for atomic.LoadInt32(&client.calls) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiterResult := make(chan error, 1)
+	go func() {
+		result, err := cache.Refresh(context.Background())
+		if err == nil && result.Count != len(skus) {
+			err = fmt.Errorf("expected %d skus, got %d", len(skus), result.Count)
+		}
+		waiterResult <- err
+	}()
+	for cache.flights.waiting(refreshFlight) < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A caller with a short deadline returns its own error promptly.
+	start := time.Now()
+	shortCtx, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := cache.Refresh(shortCtx); err != context.DeadlineExceeded {
+		t.Errorf("expected the caller's deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the caller to return at its deadline, took %s", elapsed)
+	}
+
+	cancelLeader()
+	if err := <-leaderErr; err != context.Canceled {
+		t.Errorf("expected the first caller's cancellation error, got %v", err)
+	}
+
+	// The shared refresh is not cancelled with the first caller.
+	close(client.release)
+	if err := <-waiterResult; err != nil {
+		t.Errorf("expected the remaining caller to succeed, got %v", err)
+	}
+	if calls := atomic.LoadInt32(&client.calls); calls != 2 {
+		t.Errorf("expected one list call for creation and one shared refresh, got %d", calls)
+	}
+}
+
+func Test_Cache_RefreshCancelledWithoutCallers(t *testing.T) {
+	client := &contextClient{release: make(chan struct{})}
+	close(client.release)
+	cache, err := NewCache(context.Background(), WithClient(client), WithLocation("eastus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.release = make(chan struct{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.Refresh(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected the caller's deadline error, got %v", err)
+	}
+
+	// Once no caller waits, the refresh is cancelled and later callers
+	// start a new one.
+	close(client.release)
+	if _, err := cache.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls := atomic.LoadInt32(&client.calls); calls != 3 {
+		t.Errorf("expected a new list call after every caller gave up, got %d", calls)
+	}
+}
+
+func Test_flightGroup_panic(t *testing.T) {
+	var g flightGroup
+	started, release := make(chan struct{}), make(chan struct{})
+
+	recovered := make(chan interface{}, 2)
+	call := func(fn func(context.Context) (interface{}, error)) {
+		defer func() { recovered <- recover() }()
+		_, _, _ = g.do(context.Background(), "key", fn)
+	}
+	go call(func(context.Context) (interface{}, error) {
+		close(started)
+		<-release
+		panic("boom")
+	})
+	<-started
+	go call(func(context.Context) (interface{}, error) { return nil, nil })
+	for g.waiting("key") < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		if r := <-recovered; r != "boom" {
+			t.Errorf("expected every caller to panic with the call's value, got %v", r)
+		}
+	}
+	if value, _, err := g.do(context.Background(), "key", func(context.Context) (interface{}, error) { return 1, nil }); value != 1 || err != nil {
+		t.Errorf("expected a new call after a panic, got %v, %v", value, err)
+	}
+}
+
+func Test_Cache_storeRejectsOlderLoads(t *testing.T) {
+	older := Wrap([]compute.ResourceSku{{Name: to.StringPtr("older"), ResourceType: to.StringPtr(VirtualMachines)}})
+	newer := Wrap([]compute.ResourceSku{{Name: to.StringPtr("newer"), ResourceType: to.StringPtr(VirtualMachines)}})
+
+	cases := map[string]struct {
+		newerComplete bool
+		olderComplete bool
+	}{
+		"complete data should not be replaced by an older complete listing": {
+			newerComplete: true,
+			olderComplete: true,
+		},
+		"complete data should not be replaced by an older partial listing": {
+			newerComplete: true,
+		},
+		"partial data should not be replaced by an older complete listing": {
+			olderComplete: true,
+		},
+		"partial data should not be replaced by an older partial listing": {},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			cache, err := NewStaticCache(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The older load starts first, as a refresh whose callers all
+			// left may finish after a later one.
+			olderGeneration := cache.nextGeneration()
+			newerGeneration := cache.nextGeneration()
+
+			store := func(data []SKU, complete bool, generation uint64) bool {
+				if complete {
+					return cache.storeComplete(data, time.Now(), generation)
+				}
+				return cache.storeIncomplete(data, Completeness{MissingFromPage: 1}, generation)
+			}
+			if !store(newer, tc.newerComplete, newerGeneration) {
+				t.Fatal("expected the newer load to be stored")
+			}
+			if store(older, tc.olderComplete, olderGeneration) {
+				t.Error("expected the older load to be rejected")
+			}
+			if got := cache.List(context.Background()); len(got) != 1 || got[0].GetName() != "newer" {
+				t.Errorf("expected the newer data to be kept, got %v", got)
+			}
+			if complete := cache.Completeness().Complete; complete != tc.newerComplete {
+				t.Errorf("expected completeness of the newer load, got %t", complete)
+			}
+		})
+	}
+}
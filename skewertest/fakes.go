@@ -0,0 +1,258 @@
+package skewertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// FakeClient is the simplest client usable by skewer.WithClient. It
+// returns all of its skus in one call, without pagination, and ignores
+// the filter.
+type FakeClient struct {
+	// SKUs are returned by every call to List.
+	SKUs []compute.ResourceSku
+	// Err, when non-nil, is returned by every call to List alongside
+	// SKUs.
+	Err error
+
+	mu    sync.Mutex
+	calls int
+}
+
+// List returns SKUs and Err.
+func (f *FakeClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+	return f.SKUs, f.Err
+}
+
+// Calls returns the number of times List has been invoked.
+func (f *FakeClient) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// fakePages serves sku lists as pages, failing requests with injected
+// errors and counting every request. It is shared by the fake Azure
+// clients, which only differ in signature.
+type fakePages struct {
+	pages [][]compute.ResourceSku
+
+	mu         sync.Mutex
+	listCalls  int
+	pageCalls  int
+	errs       []error
+	pageErrors map[int][]error
+}
+
+func newFakePages(pages [][]compute.ResourceSku) *fakePages {
+	return &fakePages{
+		pages:      pages,
+		pageErrors: map[int][]error{},
+	}
+}
+
+// injectErrors queues errors for the next calls to list.
+func (f *fakePages) injectErrors(errs []error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs = append(f.errs, errs...)
+}
+
+// injectPageErrors queues errors for the next requests for page.
+func (f *fakePages) injectPageErrors(page int, errs []error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pageErrors[page] = append(f.pageErrors[page], errs...)
+}
+
+// list returns the first page of a fresh traversal, so every call
+// observes all pages from the start.
+func (f *fakePages) list(ctx context.Context) (compute.ResourceSkusResultPage, error) {
+	f.mu.Lock()
+	f.listCalls++
+	var err error
+	if len(f.errs) > 0 {
+		err, f.errs = f.errs[0], f.errs[1:]
+	}
+	f.mu.Unlock()
+	if err != nil {
+		return compute.ResourceSkusResultPage{}, err
+	}
+
+	cursor := 0
+	page := compute.NewResourceSkusResultPage(func(ctx context.Context, _ compute.ResourceSkusResult) (compute.ResourceSkusResult, error) {
+		if cursor >= len(f.pages) {
+			return compute.ResourceSkusResult{}, nil
+		}
+		if err := f.nextPageError(cursor); err != nil {
+			return compute.ResourceSkusResult{}, err
+		}
+		values := f.pages[cursor]
+		cursor++
+		return compute.ResourceSkusResult{Value: &values}, nil
+	})
+	if err := page.NextWithContext(ctx); err != nil {
+		return compute.ResourceSkusResultPage{}, err
+	}
+	return page, nil
+}
+
+// nextPageError counts a request for page and returns the next error
+// injected for it, if any.
+func (f *fakePages) nextPageError(page int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pageCalls++
+	errs := f.pageErrors[page]
+	if len(errs) == 0 {
+		return nil
+	}
+	f.pageErrors[page] = errs[1:]
+	return errs[0]
+}
+
+func (f *fakePages) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.listCalls, f.pageCalls
+}
+
+// FakeResourceClient is a skewer.ResourceClient serving a fixed
+// sequence of pages. Every call to ListComplete starts a new traversal
+// from the first page. It ignores the filter.
+type FakeResourceClient struct {
+	pages *fakePages
+}
+
+// NewFakeResourceClient returns a FakeResourceClient mapping each sku
+// list to a page of values.
+func NewFakeResourceClient(pages ...[]compute.ResourceSku) *FakeResourceClient {
+	return &FakeResourceClient{pages: newFakePages(pages)}
+}
+
+// ListComplete returns an iterator over all pages.
+func (f *FakeResourceClient) ListComplete(ctx context.Context, filter string) (compute.ResourceSkusResultIterator, error) {
+	page, err := f.pages.list(ctx)
+	if err != nil {
+		return compute.ResourceSkusResultIterator{}, err
+	}
+	return compute.NewResourceSkusResultIterator(page), nil
+}
+
+// InjectErrors queues errors for the next calls to ListComplete, one
+// call per error.
+func (f *FakeResourceClient) InjectErrors(errs ...error) {
+	f.pages.injectErrors(errs)
+}
+
+// InjectPageErrors queues errors for the next requests for the
+// zero-based page, one request per error. The first page is requested
+// by ListComplete, later pages while iterating.
+func (f *FakeResourceClient) InjectPageErrors(page int, errs ...error) {
+	f.pages.injectPageErrors(page, errs)
+}
+
+// ListCalls returns the number of times ListComplete has been invoked.
+func (f *FakeResourceClient) ListCalls() int {
+	calls, _ := f.pages.counts()
+	return calls
+}
+
+// PageCalls returns the number of page requests, including failed
+// ones.
+func (f *FakeResourceClient) PageCalls() int {
+	_, calls := f.pages.counts()
+	return calls
+}
+
+// FakeResourceProviderClient is a skewer.ResourceProviderClient serving
+// a fixed sequence of pages. It behaves like FakeResourceClient.
+type FakeResourceProviderClient struct {
+	pages *fakePages
+}
+
+// NewFakeResourceProviderClient returns a FakeResourceProviderClient
+// mapping each sku list to a page of values.
+func NewFakeResourceProviderClient(pages ...[]compute.ResourceSku) *FakeResourceProviderClient {
+	return &FakeResourceProviderClient{pages: newFakePages(pages)}
+}
+
+// List returns the first page, which fetches the following pages.
+func (f *FakeResourceProviderClient) List(ctx context.Context, filter string) (compute.ResourceSkusResultPage, error) {
+	return f.pages.list(ctx)
+}
+
+// InjectErrors queues errors for the next calls to List, one call per
+// error.
+func (f *FakeResourceProviderClient) InjectErrors(errs ...error) {
+	f.pages.injectErrors(errs)
+}
+
+// InjectPageErrors queues errors for the next requests for the
+// zero-based page, one request per error.
+func (f *FakeResourceProviderClient) InjectPageErrors(page int, errs ...error) {
+	f.pages.injectPageErrors(page, errs)
+}
+
+// ListCalls returns the number of times List has been invoked.
+func (f *FakeResourceProviderClient) ListCalls() int {
+	calls, _ := f.pages.counts()
+	return calls
+}
+
+// PageCalls returns the number of page requests, including failed
+// ones.
+func (f *FakeResourceProviderClient) PageCalls() int {
+	_, calls := f.pages.counts()
+	return calls
+}
+
+// NewResourceSkusResultIterator returns an iterator over all skus,
+// mapping each sku list to a page of values.
+func NewResourceSkusResultIterator(pages ...[]compute.ResourceSku) (compute.ResourceSkusResultIterator, error) {
+	return NewFakeResourceClient(pages...).ListComplete(context.Background(), "")
+}
+
+// Chunk divides skus into count pages of roughly equal size.
+func Chunk(skus []compute.ResourceSku, count int) [][]compute.ResourceSku {
+	divided := [][]compute.ResourceSku{}
+	if count <= 0 {
+		return divided
+	}
+	size := (len(skus) + count - 1) / count
+	for i := 0; i < len(skus); i += size {
+		end := i + size
+
+		if end > len(skus) {
+			end = len(skus)
+		}
+
+		divided = append(divided, skus[i:end])
+	}
+	return divided
+}
+
+// NewDetailedError returns an error shaped like those of the Azure
+// clients for a response with statusCode, for use with the Inject
+// methods. A positive retryAfter sets the Retry-After header in whole
+// seconds.
+func NewDetailedError(statusCode int, retryAfter time.Duration) error {
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+	}
+	if retryAfter > 0 {
+		resp.Header.Set(autorest.HeaderRetryAfter, strconv.Itoa(int(retryAfter/time.Second)))
+	}
+	return autorest.NewErrorWithError(errors.New(http.StatusText(statusCode)), "compute.ResourceSkusClient", "List", resp, "Failure sending request")
+}
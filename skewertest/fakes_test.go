@@ -0,0 +1,199 @@
+package skewertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/alexeldeib/skewer"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_FakeClient(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+	client := &FakeClient{SKUs: SampleSKUs()}
+
+	cache, err := skewer.NewCache(ctx, skewer.WithClient(client), skewer.WithLocation(SampleLocation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+		t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+	}
+
+	client.Err = boom
+	if _, err := cache.Refresh(ctx); !errors.Is(err, boom) {
+		t.Errorf("expected injected error, got: %v", err)
+	}
+	if got := client.Calls(); got != 2 {
+		t.Errorf("expected 2 calls but found %d", got)
+	}
+}
+
+// nolint:funlen
+func Test_FakeResourceClient(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+	policy := skewer.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	cases := map[string]struct {
+		errs          []error
+		pageErrs      map[int][]error
+		retry         bool
+		stage         skewer.ListStage
+		page          int
+		expectedPages int
+	}{
+		"should serve all pages": {
+			expectedPages: 10,
+		},
+		"should fail listing": {
+			errs:  []error{boom},
+			stage: skewer.StageList,
+		},
+		"should fail the first page": {
+			pageErrs: map[int][]error{0: {boom}},
+			stage:    skewer.StageList,
+		},
+		"should fail a later page": {
+			pageErrs:      map[int][]error{3: {boom}},
+			stage:         skewer.StageNextPage,
+			page:          3,
+			expectedPages: 4,
+		},
+		"should recover with retries": {
+			pageErrs:      map[int][]error{3: {NewDetailedError(http.StatusTooManyRequests, 0), NewDetailedError(http.StatusTooManyRequests, 0)}},
+			retry:         true,
+			expectedPages: 12,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			client := NewFakeResourceClient(Chunk(SampleSKUs(), 10)...)
+			client.InjectErrors(tc.errs...)
+			for page, errs := range tc.pageErrs {
+				client.InjectPageErrors(page, errs...)
+			}
+
+			opts := []skewer.CacheOption{skewer.WithResourceClient(client), skewer.WithLocation(SampleLocation)}
+			if tc.retry {
+				opts = append(opts, skewer.WithRetryPolicy(policy))
+			}
+			cache, err := skewer.NewCache(ctx, opts...)
+
+			if got := client.ListCalls(); got != 1 {
+				t.Errorf("expected 1 list call but found %d", got)
+			}
+			if got := client.PageCalls(); got != tc.expectedPages && tc.stage != skewer.StageList {
+				t.Errorf("expected %d page calls but found %d", tc.expectedPages, got)
+			}
+
+			if tc.stage == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+					t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+				}
+				return
+			}
+
+			errList := &skewer.ErrListResourceSkus{}
+			if !errors.As(err, &errList) || errList.Stage != tc.stage || errList.Page != tc.page {
+				t.Fatalf("expected failure at stage '%s' page %d, got: %v", tc.stage, tc.page, err)
+			}
+		})
+	}
+}
+
+func Test_FakeResourceClient_Restarts(t *testing.T) {
+	ctx := context.Background()
+	skus := SampleSKUs()
+	client := NewFakeResourceClient(Chunk(skus, 5)...)
+
+	for i := 0; i < 2; i++ {
+		iter, err := client.ListComplete(ctx, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for iter.NotDone() {
+			count++
+			if err := iter.NextWithContext(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if count != len(skus) {
+			t.Errorf("expected traversal %d to find %d skus but found %d", i, len(skus), count)
+		}
+	}
+}
+
+func Test_FakeResourceProviderClient(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+	client := NewFakeResourceProviderClient(Chunk(SampleSKUs(), 10)...)
+
+	cache, err := skewer.NewCache(ctx, skewer.WithResourceProviderClient(client), skewer.WithLocation(SampleLocation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+		t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+	}
+
+	client.InjectPageErrors(5, boom)
+	_, err = cache.Refresh(ctx)
+	errList := &skewer.ErrListResourceSkus{}
+	if !errors.As(err, &errList) || errList.Page != 5 || !errors.Is(err, boom) {
+		t.Errorf("expected failure at page 5, got: %v", err)
+	}
+	if got := client.ListCalls(); got != 2 {
+		t.Errorf("expected 2 list calls but found %d", got)
+	}
+}
+
+func Test_Chunk(t *testing.T) {
+	skus := make([]compute.ResourceSku, 7)
+
+	cases := map[string]struct {
+		count    int
+		expected []int
+	}{
+		"should divide evenly": {
+			count:    7,
+			expected: []int{1, 1, 1, 1, 1, 1, 1},
+		},
+		"should place the remainder last": {
+			count:    3,
+			expected: []int{3, 3, 1},
+		},
+		"should return one page": {
+			count:    1,
+			expected: []int{7},
+		},
+		"should return no pages for non-positive counts": {
+			count:    0,
+			expected: []int{},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sizes := []int{}
+			for _, page := range Chunk(skus, tc.count) {
+				sizes = append(sizes, len(page))
+			}
+			if diff := cmp.Diff(tc.expected, sizes); diff != "" {
+				t.Errorf("expected page sizes to match: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+//go:build ignore
+// +build ignore
+
+// gen_sample_data generates sample_data.go from testdata/eastus.json at
+// the repository root, so the sample dataset is compiled into the
+// package without a second copy of it in the repository.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+)
+
+const width = 76
+
+func main() {
+	data, err := ioutil.ReadFile("../testdata/eastus.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil {
+		log.Fatal(err)
+	}
+
+	var zipped bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&zipped, gzip.BestCompression)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := writer.Write(compacted.Bytes()); err != nil {
+		log.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		log.Fatal(err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(zipped.Bytes())
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen_sample_data.go; DO NOT EDIT.\n\n")
+	b.WriteString("package skewertest\n\n")
+	b.WriteString("// sampleData is testdata/eastus.json at the repository root, gzipped\n")
+	b.WriteString("// and base64 encoded.\n")
+	b.WriteString("const sampleData = `\n")
+	for i := 0; i < len(encoded); i += width {
+		end := i + width
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		b.WriteString(encoded[i:end])
+		b.WriteString("\n")
+	}
+	b.WriteString("`\n")
+
+	if err := ioutil.WriteFile("sample_data.go", b.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
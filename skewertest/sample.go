@@ -0,0 +1,47 @@
+package skewertest
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/alexeldeib/skewer"
+)
+
+// SampleLocation is the location of the bundled sample dataset.
+const SampleLocation = "eastus"
+
+//go:generate go run gen_sample_data.go
+
+// SampleSKUs returns the bundled sample dataset: every resource sku
+// available in eastus, as listed by the Resource SKUs API. Each call
+// returns a new copy which the caller may modify.
+func SampleSKUs() []compute.ResourceSku {
+	reader, err := gzip.NewReader(base64.NewDecoder(base64.StdEncoding, strings.NewReader(sampleData)))
+	if err != nil {
+		panic(err)
+	}
+	defer reader.Close()
+
+	var result compute.ResourceSkusResult
+	if err := json.NewDecoder(reader).Decode(&result); err != nil {
+		panic(err)
+	}
+	return *result.Value
+}
+
+// NewSampleCache returns a static cache for SampleLocation holding the
+// sample dataset.
+func NewSampleCache(opts ...skewer.CacheOption) (*skewer.Cache, error) {
+	opts = append([]skewer.CacheOption{skewer.WithLocation(SampleLocation)}, opts...)
+	return skewer.NewStaticCache(skewer.Wrap(SampleSKUs()), opts...)
+}
+
+// NewSampleResourceClient returns a FakeResourceClient serving the
+// sample dataset in pages of at most DefaultPageSize skus.
+func NewSampleResourceClient() *FakeResourceClient {
+	skus := SampleSKUs()
+	return NewFakeResourceClient(Chunk(skus, (len(skus)+DefaultPageSize-1)/DefaultPageSize)...)
+}
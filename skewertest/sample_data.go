@@ -0,0 +1,267 @@
+// Code generated by gen_sample_data.go; DO NOT EDIT.
+
+package skewertest
+
+// sampleData is testdata/eastus.json at the repository root, gzipped
+// and base64 encoded.
+const sampleData = `
+H4sIAAAAAAAC/+y9bW8bR7L+/VUMvqZzd1dXP8y+syU5CW7bMVYnPsAeBAZjcU+ElcSAlL2bY/i7
+/0HKkqnhcLpJ1Tz0zPXOsJojamb6V9X1cNWXyefZ1af55G//82WynK8Wn5Yf5//115/zyd8ms8+z
+y6vZ75dXl7d/nc9vV5Pp5GZ2vf7JydVstbr8OJlOrhYfZ7eXi5vV5G//M5nPVrefVpPfvv/3zzf/
+XGwuff8fk7/dr5pO/m9xM19/7re7f57Ob2eXV5v/+PrbdPJx9ufd777cLPpy/7vfzP5zef3p+t3V
+7Pafi+X1q9mnq9vTxfXs8uZk8enmdjK9/4MmZrK+znK+ul1efrz/kr99nR7wh764uvzfm/nFUP7Q
+i8vVv7b+uvPb2c3FbHnx4fXfzyfTye3lfLn1v5PpZHX5f5t1LHQDJmYyndBkOtGTg+/F+eX/zX+8
+fLn9Z9Pk6/T7ksub3SXq0YrZf37+5c/V1o+tUqVLRBbM/vNydnPx78uL2z/evHy00pWvlLhw9p/3
+6x/88s/1n/jHbDnfXqubfrCulw/WcfTBGsKTrX2yWvXy0WoK0WfrGM+2/tnaXj5bsi76bMvPHw+3
+/HCpnxvX6ri1Lb8AeLjlh2t6SmVFcZNbfgPwdHfc5H4+XVIcN7o7rwAeb/nx2n4+XlZF3O7uvAN4
+vDtnoX4+3qCLuOXdeQd2Hq82cs9391JZPGDfU+PrTIhb353XYOcJk4o9YW2SH7FNfsRG9ekZB9XT
+UJV3Pm6Dd96EIx4yqQYesm3jIb9bzq8vP10/fsbf/vP7I36npZ7w+ukaMSv81FikptjupdTnSjbx
+sZI96Kk+/uWflqvbnxePWVBhQ9bL9v1+7SuXn35afnuMby5vHqFme/Vm6clyfnF5+/LTx3/Nb9e3
+/Oebn395ZH8CK5XysfJJ1jb7ElMvX+L4MYHxEuMlvn+JTS9fYh0/DQW8xXiL799i7uVbnJDc1A6v
+MV7j+9fY9fI1FkjlEsdOPJx+4Ek+7wz8NbbBhaNeY9306U7106noV95aJ5/ctRr8q7zm8RGvsibT
+8Ktse/kqi6TptY4GG3X625zsXuhD/AvK8W1mQ0e9zb5oOGDRTy6LlCVQNDdC6ZFznexk6EO8jCxf
+ZtLuqJfZNB246KmTIVWGEfczkg9+yY4GHeJoNH2k7+fzFSrE8FFH0qd7kpSMK7L9ecC2nw9YqBQD
+D/id6+cDlirGcPFcves+j6uafca+p1ZYqB5Dh/hDDukP2SfvY2979JCD6ml4XbAgI6EiI/UpF8lb
+uWhlK9+X2Jyfn8Zqb84GWpgx9MLIlEc70HIFPNrJ2VCT+Hi2k7PBprbxcCdng0344uFOzpAGHfbj
+HXBqEM93cjbkbBme7+Rs6AkkeM6DziDhCU/Ohp1CwhOenA07hyTaCcjJYWdWPXvKA88icfQxszpg
+K6fv5Z495qHnkeIZYdd9rvDI5/zr1e1ytvOQN//7/Qn/2s9wlrXGHZVm+Pt8dvHfy8vbeTmpX/WY
+KxfXP+6qj1TYhOhn9EE1H6Vvvr4V//9lyULs3IyqVbuhhp8Xd1/wl5urv2pvw76FTiX8+eUPxW5x
+aT2pyq/zbr788fJl1e01kfWl61dUotZenmpXl7/89uKXl1dXlzf/+262vL1c76X1Q3r0GKfPwvSZ
+dtNnhqbPHE+faQrTZ2Td9JnVNH22PmZMn61d0ekzozxNn62dls0P1fSZ08zTZ167MH22tnLTZwVp
+d/cpNX2mNd1dksL6txizuSIbs15ijVsvWUNz+kx7VuslgdffQxds3d1vVdNnpK1afweym69mrKUp
+sfVuStYpNSXniKfkHYcpBedpSoUr3HT9ddXUaM88XRM8TI3xBU0NB+2mxgZWU+OC46nxIYSpCYWm
+qSkK46brP1JNWReBp2yUClNmZWjKVlk3ZaeCmrLXiqcc1neMC21papX2brq+NWpqiYin1hCHqWXy
+NLWWCje1zpCaWm+YpzYYF6a2MAVNnWLtpusbqqaO2PHUGQ5h6nj9HL4RKg3MfyxWtz8uF5/+XP1/
+639uMfr0/LN5vl5Fk+nkn7Pry6u/Jn+brL4Z4vVPX939p6C8hp5MJ3QgpT+fvPv1kQF7hJHNT9/N
+lyeLZWmXPPX+nNXen7PR35+H90fn9f48zla08P7ovN6ftu7Pm/PPtP/+rH8qe3+O9RLL94e1a+sG
+XeMO1d6hV/evUCWiX/X2Bnlq5/68rt1ir8Xvj97cn6fdm6DauTdv39fyefNj6bvzxG0VWjLsL84/
+c41lX/+4n6araMs1rL9DZ7hDb86v6yyXPJafurlKRQ5N3hncmD1Art9Vb983sKt6dW9WN7M/V38s
+bg+RwWxuSs1xXzjSJNK3r/twf//Rx/v7+XJ5+2l29Wb28Y/Lm3nV136pr1Z1WZRvP9/ZTC/PW/OM
+v0zebr70vXv4zYjt22P3qYMXdyOarrZ30n8tP80nX3/7Govq//3bbXy/uPp0PX9Tl2b95fz9Hxfr
+SOibxwUV7K3d9dL3xZF/+uvP+fL9j/Ob+fLbvfy+8L2evn90pTfz68Xyrx+3f5/6oaxycTq7nZ1e
+rv5Vnvz06EqvF/9+t7xcLC9v/zqZ/Vm6Wa9mV6v59upvu/DnX3bu6Pc179+czv+8Wvx1Pb+5Xb+E
+23/Iz7PZ+fbak8X175c384v/ml//uf6qL24uTmYf/5hf/PzLu/P9feJ7P7aOX7/863a+Ruj5/OPi
+5uLxEwnWu7QrbWLmB13q15uPm0+uL1P6+nr/yoN+xdmff8yv58vZ1S/n68+ef/rzz8Xydn5R8zTO
+bj4u//pz/UK9uP1psbpN+cyLjx/nV+vXcH7xdn7778XyX5c3/3t2s345Lurejr9fXM8Slr2Z/efb
+ZX++uZ0v/zn7WBatkEPbdQRt10Bb/9FG+YFtcz+qHpJO8Pb0MYgMeSPSMRDZDSIjhAQg+w9IDUDG
+Acl5A9IQANkJICniQxJ8yDe1dYtHM5IkGRkSGckZMJKaYiSJQJKMLagQgWTVpeoO2sURlKz6HaOg
+pJGkZASSYGRdCX8/EMlAZByR2on4kdZTcJx2pYgfWXGpOkRSOMKRrPgdQOSBiOSII8lwJN+U22SC
+CCVZ9LDtEjEZMsAkN4VJI4JJ47xSWsSTrLpUDSYpHIHJqt8xCkyyICZDBJMBmHxT2212NCaDJCZN
+atZGuww4GRqLSpbiesdx0hKvgSXByapL1XCSj4lLVv0OcPLQxE0sMKkRmXyMwCIYJROZ1LLn7jAk
+VGrax4MIOvpFgPLHZADgJAHgYgBwAMDjnW208jKBN+0kCeBSQ28mixSuO6qAxbGAJ3Sf8pTM0I7c
+F2qGhEEy+aBiOVoFEu4maZVMCkKJpmnVkEiYeLYrh+iVTDHfxtopGRRWXQso7B0KT8/1h89Ux8KH
+FVV6Cz1pdq5n11ofpn81eCa5/yI9fVp+3bopwntx8pj3WjVYoieRNDDWMhtKulIkaVB1qccg2/76
+TnsVilD6QH1R3xGYVMZox6G/mOx/Vd/pOUU5SZlzcqOM1b8qEy+ePu0Mk9QNJoMIJp3XKgTHSVeq
+x2TlpfZisiDD2heu9IH65pBjip+Vc4YK119ONuNOimLSRDFpMsfkRuOvh2UmLJ8U6AyU3A0od+ah
+HnuQZtLeU0i6VOwgXXWtvazUgV0wtjClT0QK/I4oglbakLMFjY2WLElLjtKSM6flRmm0f9UmFOTD
+hp3RMnRDS0MitCQX2Fi2TqL9o/Jae2lpXGGc18GXPlFX52fdEbRkRc4aHXhstBQNVdooLW3uR3DN
+LoQeJpxtahGzyyFa6boBppOJV1rjgleFlghYVl9rLzC9Cd6w8VwkV+1s5MgPBmZQ1ihnXAAwn5Lb
+0c8T0jsPi3Akn1AnR/IwqAzPu9lyfnO7vvtbax69levXDbHOSsB6owrW2iQf3xHr7CzWqVPgCrQC
+rcgKgZTjJiWlOKL0XCPa2URuiMKAckOSnig16IkOIJlk2ekQiJx1SCb1Ppmk6TmlQJYAWUC2nz4p
+mAlmtszMBGKCl+ClXMESnFIAdkSANSlOqXlOyNw3Uuhk3YAKnUiQsqZByg6gMkoVZLxjazShMqr/
+iX7znFMoy6AsKNumLwvKgrIDomwCY0FYEBYF+wAmgHl6rjnFLeXs3VKiwnjXw7J9rWlAdfuSjik3
+6JjmX+fvdGBvqVCOGHX+OXA2pHA2gLPgrKh7Cs6Cs2PibAJlwVgwFj2oYCaYecfMeNe+brdt/05P
+WbxJqo/qyJrVgJhJqhtmrjulBFullGCvlDq4WUod0C31hHYpiDI/VWz0w7vl4noRlxx9WFeJz80P
+oT7aI/XRnsy4RaMp5Ef722j6ZXJ7R8vX9yT69oESubY+dgevL9WM+7peOlstbk4WF+vLvl3cPuyQ
+V4vl+affVx+Xl5vbtvnq3377PxY38yf+5i1w6sn0GzwP+z5P0WJNsSJmOFZkROKsPRkCjOYwqLP2
+vHcBxkTAmHCiMeHhGJMRadf2ZFIyauEgXtvzkA6MiYAxsYnGxA4ovjUmad++zJJFXhXavrAnw7cn
+Wm8bipgeJ2JdmUlzImUCbU6kTJAyacGQpGbeNSHOlaFAFJImEHxC0gRJk7bMSWoKXhtEunKUEEDe
+BBoCyJsgztWWPUnNwusBpeHH1ZGG1Ala0pA6gUlpx6S80qs6Q3L34x3z8eq8NbvxZfJ285X/51vb
+3vrm1FiSX69ul7Pz89Mqvt29mb99fYIpYlUIGSLRQFmiFeJBDfeRNEEyFsgYa5mNhAGqvNT+EBmF
+wIXSIaTmW8wx1scqY7TjHhufHbPSu1lor6geuATgbqMt6KKHKW4Mnxx5gpusd0VQxnuHBHfPgcv1
+wGUA9xFKnQk9zAWPdFYQMsGrRzETawqnyi8eEsE9nPzzKtRjNwC7Lx+dx7zrYcpUQ3UdGdOJVkb5
+grTVipEx7bl40CvtIiFdB/RuM85Za/qYXTSQu0Ruce2MOm0DG0UqILfYd+U2Wn34bGpLM+5XVNVk
+GAQd+hTmDcPJq9WFebVLwS+NMK/2gGqlkFfrd5j3lKPkZZC3wbgDdxJ3CHmHextEb+YZNq288awD
+sVJIsfWcvSHK3gD2Nhh4CJ3EHXLItYVu4Jt/ro00ew7GcVAKybaeJ9tOtYsSWDsgeDfwYLQS0lCR
+Df46HlDiTbtuKJx/5o2p4ML59TwNhcxb34O/Jh79NQj/VjT4OdJCAiRGVsqqCandzjBsOgoC55+D
+CzYUhWFbpAeCkYPrDMM6NnBO7xs3Jz1tznzr5ROWayq/Wm10ocWCBj/YTpJlO+9ZjJTTdz1vRTMi
+sORAhrkQcVlZa08HZ6d2nkwKk3Y+NJz8FMWoRHlTSStiISyRGJZ8J2mkBqHUUbuWTDiz8FZ76ySi
+mVV86xuU+p+3MTEmmbyZRIqDEJNYjElNzALrmEpddTTJxPd04VQwXuRgWYW4cXBJMp3BMS5x3lxa
+y4gIcSmIcamrWFeDXOqo44eEyh4LQ+mJ5Ej2t4px4wCTZGjJxsBk8wbTWm5DKrbkxMjUxISiroNL
+HfXDsExZoA+ODcmgqRJzQNPBUe942FsjwiQeYdKMEBNCTAgx7cVSNO6tCUEm8SATBQSZEGRCkKmO
+TNHotzYIM4mHmaxDmAlhJpzlatEUDYBrRqBJPtDUyEAHRJoQaRqWxknaDJrIgMxGBtCgtKnpKcsH
+kwoxJ8Sc5hhp3NnMsMQJlGYwtB5N0VeHvEYsDrE4TA1uiNiJMx55MMQeTTlch8RGjBIxSkxRbArZ
+Ng3ZdjDIHk+hYIfMRugWoVtAuzFoa72N40jdJCLZOZVQIpSNUDZC2YMDdmLmUROC2bkVlyKYjWA2
+gtkDZHZi/lEbhLNzK7tFOBvhbERGhgjtxBSkZgS08ytIRkQbEW1we4DcfqXrB/9Wjv3NImwtNh9S
+TvGW8tC7hdQtpG5b7vl/Vdu69oqyxZDcsES55Fkm8iNInSF11jKFuJZCnC2F5MYGymWEclEbQT4I
++aC2hUZehVoQhXxPZXLD8+TyHDoXeRGkOZDmaL13/5V29SEily2NBIfICQbwTS6CIgjfI3zfPo9e
+xERrX+zTrH2RkWZtppFrGsKkNq2aO7DJTBdWhdeWRHwkxcF6pxC+fhKT6DoGJbrOm0qbquK+dYG4
+oQ2QpG6wJBNGYl2wUSyBpSrCAUuHYylKJUCpq+wamAQmjZFJHHWVOHNXaVPM37NsW3JQKQxB279B
+LMmEuYMJwakggaUqwo0ESyyJpSiVAKWuSgDAJDBpjEwKUVcpZO4qbVpoelYO4IY3bDt0wyWZFJx2
+3vt14kwATFWMGwmYJFNwIcolYKmzKiVgCVgaJZbW40NMbL6Iqe46NjlgyWrqnRJbGFW426VAidDY
+hnD3ljK7iWmy58wkQXlIbt9XGkZkqTkqoc9tkBO2QwxKIW8oCUoghvZTcAM5wDVHJbS9DXW+touB
+Sbu8ySQo9Kdd+yHvfAZsa9cNm9AHN9QZtiYaXjKZx5cEBe2MYJV3E2O1OqaT6SjKhK64odKJo2c6
+bvVQdydjJxppIgpiwvaCebmCBocn7uhgV8jEm+6b2kT4ZIk37x349CQ+uWgg3HHmfForTgrxyQlO
+3rBucHxyDD6BT8L+0yruQK32Eeq8RUR9mbzdfPk7qWBzv2gftH69ul3Ozs9Pq/bR3WP57etTBC43
+7nvrTtn0PfXVLyuvkRGYy9sjC8oa5YxICrD6WpsPrq+y+dzj8EHgQulQPjz8evPx4UOlv9q7mrX7
+sU7KF7YoveUJKC6/MSkkLn9mQI7iKu4pAsS7xWEbP6B17zMO4s4c0EZA3JXrqUmobFV541kHYhH1
+mMqL7Wex174IrtCB9/O1olr3GBbbQEW5XhIsPojFZ7GMx9m+hMdZHkf2PhbUjkzUBhW1qKg9DEqx
+QOIZ5w2lXlbUjkw/AhW1qKg9CEqx7OtZyBtKvayoHVunNipqUVF7IJeiFbVn2uVNpn5W1A6xaA0l
+tSipFY4vqWiASQ0gwiQUYlKCMSY1pJE3pLoBk1EyIaZC+1CeRHJ0jMka53xggOkpYIrW+p+ZzEPf
+/az1H2KxGmr9UesvS6dorf8ZZx5u6mmtf2mkPGr9UUsLPu3yKVrrf+Zyz9H1s9afDaHWH3wCnyJh
+p1U07LSvwPRsxJX+5F1ou1gqoc6/m3qpRopLuzopysTXjbVcskDHnhOrLrW3qtQaF7zaKfasqSk1
+dERJqVXGaHdw+VaLBaU5FG89j8OXnwO/u/h11hrXdlVYHL8dFYZ1jd93s+X85nZ997fWbL3mmxe4
+IVzLxPWc1yoEJ5F0qLzU/iaAh6aBZF47PqYFQCnnDB1c2ZZ9B4Aor+O0BqvB6g4aYrtxlcFesLe1
+muIEXznAV64KzxqtPLVdrRwHcFcFyz33lkOT3rIWiuMaJu09SbRcVF9rL7RJs+dgHKcLGGiqWVsz
+4VgbcrYYnX4Bi1KbE6gNrxnU7rvfDGqD2qOhdpzZIDaI3QWxu2oJBIFB4FY7FRMc561F4PDWC+jK
+7SOt9EAmFGd01QbZc99ZuyadZyPTzU0usLFsRbRvKq+1F91MBRfOU+H247jcJWrdEehmRc4afXC/
+FGTCHqM7pKAbLjTQ3X8nGugGukeE7gRwA9vAdjfY7kpyBBgGhtsVQom3pChguLonpX15lZSuFDUg
+DJPqrjFFsDNFCbamqMYwvGN7DupOgd75k2RfEgIZW4uA4kbmTxjC/InmAxmGmgxkOJmmwofePonu
+6cpr7UV3sKEoDNsiva/Q6mMaCysHGgHdB6M7IZaxvQrwBrx7HM4AvUHvEdE7Ad3gNrjdDbe7knAE
+hoHhdoUl4+3efYt9PFrUEYbvNFJ7OAS5M8FKDEHGEGSA+AkKminRjO1VwPHWkwjshfQ3ZEchd6bP
+2fdohmtUMAmzkzE7uQuEJ0Q1tlcB4UB4jwMbQDgQPjaEJ/Ab8Aa8u4F3V8r4YDFY3DaLX6g6DL9Q
+VQR+oT688KIIFp+oLdNAIjY/RP3gbapyRjIfd96atsfW1lDSpkBS93naRpua718mt3f79R+Lm4cb
+VNpPWx+521Jf9u28+031zV2ZTszkt6/rC8xWi5uTxcX6F71d3D480leL5fmn31cfl5ebWzn5eiRJ
+dC1JdH4k8dr1jiQ6nST5zL+uc7cUSDI6klAtSSg/kmizVoZue8ZPLIf9g+1kwk+DJCGQBCTZJklt
+kOmFyfB0szlYtz0CIUIS38n4gyZnt4Ij4Mg2R2wtRyw8EpnDDcMhAUgGDRKuBQnnBxKnC0uqbali
+KZAI6xQ3SJIAkkgJ6Q6DJK6WJA5HGxGSJKt+4WwDlyRPkPhakHi4JCIgsQ4uCUgyRJfk5Wx1+fFR
+Icnmf/ZVkfy+/uELlI8Ms3wElSPwQA7jht7PDZ0RN1gVDsUinWED/sbYuEH7uUEZccNpRmVIo9hA
+DgbY+I4Nsx8bJiNsaKIQUAfSHDUQJgU1vlOD91ODcwpusPUOydomuYGgKLixnV451fbD59qC9ocV
+O2mW088kipLNn30nrynqiygq6ym2Mi0kypXUUSEun3qyuoEhpA/GS7JgpszAkCKQ1daLCGZyobwx
+9mBhylZxmYMWw2Vci+FynxbDzy1qMTyFUM4EFiKUkzsvdSab0CChHGetJakVB+uTrxXR9N2oSKuD
+BQqAqDKiEvRiLvcKxvwMxRgoxkAxBooxUIwRwPEbUuF6FTnTbi/aAfKb8ybOtXe4rZ0r0R2HWRds
+FAsdkdNjbzEMW6+OOyS/mf1nQ4GHt3qxXH9o9eLqavHvR69qkK1Iegq/R7vTG4qkUSsRtDVLUngD
+3PQeNxQscAPc9Bs3rN3zFOQ8Xgfs/G0STAhOyYzNYu3EsDMuL6cmeRDSdafXr/fm1W7o6EpWJqWg
+N6kJJROw23Ox/UdXq3VQVZ8RP7tS5a+BicnVxKSYF5gWmJaDyl7g0oI3NS7tdapPi9Bd/8mjNcOr
+PcarvYZbC7cWZqYZM5NkYmBeYF7g2QI5T0fOT6GONT+FKsj81ARh+lpP3YGATT61inW9GlQ0Vkxt
+JRxEY6zlUhXVke5h1aXQd9JA30k7RPzwbrm4XtRz8WHNLh03PwEi+4LIDmV6gEfgcXB41LW6qXc/
+zs9l3KiWCfHwgENktAVP0+Ba8LQDFkeORR4iFhPcxq1FmfqNY+JklwKPYCQYObyT9XX9ofoa0cYO
+iIhwI6CI83SHUEyJN14j4JgNJRFxBCFBSMmj9XXkVH2NmKPcWZqIEXMEGHGezgGMSUHHa0Qd8yEl
+oo6gJCgpSsllhI9LuI9IWQOMowJj+Y0aJReTvMclvEfkrAFJQHKkR+yY93gN9xHRR5ARZBwfGdOi
+j3AgEX4EJoHJMWLy7fvadpm7H+9g8e37viPRhLUMvMxchPbbBymHsbuP/pgfa0acYwzeCMbgvX2v
+qZ4kmvJEiSucEUKJpg6ic5zDNF5NNTQh0GR0NCGupwlxnjTR7NkKjeglHmFYK00zkGtwwsBJD043
+7eIkoft1e1EFWLKIBI3m2NNhGAjnHngqj889KWzRlD1cRnQQ6jLKjJMQ+PL4JJTCF+Ls+TKmo1GH
+gMHZCGej7UT5S01qGVP1frRqN03+crmSFvaWZctmeryU76JEB2W78IO3iYQJ/R+VXbo9QgxpUZq7
+mdQxyQ1ofnsSHS71fcmuM3By3u+tapiV47ZjGNMEwfzUg4bOYaJ9y1GM/m1guADfgaIpThRN2SLF
+haAptB65iDMl/WwhXBLSkOlvOXSRP1TCgKFCvIxT5fui/LCijXdknQhXiCW5whwSuWIy8FVaj1hk
+f9oYNlZSqAKoACqACpyVNKq8ip5/Xu09/bzqZqbh3YL1TeputqF2JshEakRPValnqgyOVFTTcqQL
+29SUWxaZcWvIKhusS7pSpOeo6lJ759saNrbwJhgXEofbGjpiti0XFIzyNDpDIBjMfhX15l4xyLsb
+JCfvhGbKSpI3DCePxd2QN4iQ1znlFIUgQN7KS+0lrwteF+ydNy6RvI4HSd5mBvxKgjdEwRsA3t1U
+grXGta0Sn5CcTK2xPmAkd2foDd2gVzsR9mpjtCu0lWi1r77WXvpq49mEwhr2tI+p5T+awhH81UVB
+VDCrsQGYBQGso2Uc35cAwdtvuS6Z/lbUTuIQNjSg+Kh23VB4fRIXoDA5b4ILQQlQuPpaeylMnoP3
+hXIFcyKFybojKGyKwDaEAAo/gcImHvo1iP1WiE450iwT/DWi0V/HA6Kw6SgA7GQiwNa44FWhJXzh
+6mvtpbDlwlsbtAkhNQhs9TFRYB8c7xitMVA4SEaB49EIRjiiIg5cGNIy8QgWDUgUbkAU5o4iEoVM
+RCIoa5QrJcOOpHD1tfZSOBA7YzypYFIjEt4dFZHQ1rAxDhh+CoZdPBvnkI7bxbAlLqVIju9ZEU3I
+lcreM+ew6ygptwmSSoSGlTeedSBOulYkNlx9sf3BYVUUVmtH3vvU5Fwod02Cxa2x2McDEx6BiQoW
+h6K0J45msRcNTJTCJZmz2HcUmdAsE5rQpHxhCyuTp6u+2H4Wr5V0greeOCiwuO8sfnviIt3W1a0G
+0n0G38AKuTioZEPNKddO61iXdZ4ogUo2tOFAk/Z7IWN9kHnSBCrZUIKDElwXig1RtQYABUABUHKd
+KdYuT1JU90/qVPdPGhCuRRgFqvs4+eQfR0lhi6bs4QLVfURWwJcOjkIpfCHOni9Q3cfRCLGWTmIt
+aYRZAjFADBCD6MthhHkd7ah5vbeh5rV49aCunhb0vXLwYUFXTeXBlHvL+qHskdzOmJ2yR41+G5Em
+suxJQeGnOSBROyCK61y81g4o+v72O6elKpi1QzdJmsIFaNSxe9QSjeJ6D68NgUbf6/wNGcehh0IP
+ZN1glR5Ao471x1uiUVz34DXjmPaoiTFYFlL/klU8KEmhD0ryADgaB47i/f+vHQNHW5Ftx54K18PO
+f6tpsJ3/wNE4cBRUPIatgKNyEFsm0xaUbBhbDQhHQQFHo8PR2/fxudvv98/dft8AjzB3G3O3xz13
++/X9BnkaTA7Cx3QYJUjvE6Z+v9eULdAw9RtTv3McpAmkHY+0hOHA74mzRRqGA2M4MJg2RjfNJLhp
+Zh/TzEjOnZpw8DzKTdOHqjXi5AmkPdVNMwluWp5IEzx5EuPkeZSXRkAavLQWkcbhG65qkMYhW6RJ
+njw54OSZVnd1xMkTTAPTZJh2ehIisbSHFTs8Oz1pZADBngqMJ3CNVVHSRm+h7U9skGwOorXh4EkA
++2XzgzLWjnDuv5bTsj890avottar3Pf1zizH44NKYts6uZM3g2iS3KYmUqRU4Vrb03I7ieI7ibLf
+SVoRC20lEttKqS5/Drkmua3EVoXgXWhtKw3RPHJ8U3P2m5oUB6FNzWKbWqf2c2YQm2S5XV0o7b01
+Drv66F19RhcfPtfOSnhYsbOnz04/cyt9BE9p0nahdROt+7mbG9LW6mjYXSE1684GR6wkxvAX3oXS
+NOcx650eCSSOAolzBpK2xrXuX+gxhdU6GoVsgpUgEjERaWVd0qWiwzd36dY3JLWoFnYkkUKUSCFn
+IhnlqfVAv+6puF8Lkf72kOS9iJPEgZmpVPR3JJIq8TYOJLEgkrSLMkm7nKHkNtOsW1f666vMX0Oz
+HVxHhzcrM6m8cD4YW9JgOxJMlZAbB5iCZDhJxeNJKvuAklBESQmGlNSQwESqIzCto0oyYaVCqXKp
+85FkcsoUvvDegUxPIZOJR7pN1qFuTRSkgt1GLtrdmRhpM2gyHQW8jQoyaCqMdV6XpNmORFOlAwY0
+HZ6DW8WTcKu9aDr/zCOT8+okqzcdVZoeeb26vN7+mgRrXPAbrblHv/vXm48PHymTvWbp/i+ljNGH
+gzf/ngbRVOPzBPLyc7C3+wTmtK+tAV3D991sOb+5Xd/9rTXb2fTNuzvynGdN44TyxvN6qkwyrx0f
+wWutlHOGCoexTU8pDYnTGqwGq7urZ0W5CdA7zBqYFFc5wFXuQWXNyCZnyvnKoVFfOZdinJp+xPW4
+6rCeeZYMbE3hCGKT0oacLQhTBZ9CbE4hNhxmELu/HjOIDWKPh9gJvAatQesOJ9Ojdh3wHWpFfYq/
+vL0KCO6uTn9sE/nlXGbtGvWZsyntr9GKoYILtx5emsxtsu4IbrMiZ40OPDZuB1luhyRuw3UGt/vs
+PIPb4PaouJ1CbTAbzG5dwRNdsUDwGBBMKqHvRAHB3Xf/prSeDGmKPPp/Y/2/ogxed6Ac34KCAf5P
+6kpOCV9srwKHO+x1Thgk1lW7c8/jF4aa7UTJpj16P7mDDUVh2BbpvYNWH9M8GJQ1yhkXQO4nkTsl
+hvFoGdgNdvcyjgF4A97jgncKuYFtYLt1bENgCBAeBYRPo4Jsp3v12E4xeuQoMbaAySOYPILJI9U8
+ik4eOeWcedTLySPJkmmYPILJI2ObPHIanTxyGnImUi8njyRr02DyCCaPjG7yyGl88sipdjlDqZ+T
+R5JbSjF4JLsSS2MKc/gUW2hoP+JSXN7/1GQdTuqpvP/ARiIh+g55f2k0cfwYx1mf43RgI3WQY7mT
+nC5oSGjijs5y7MrlwUDAoQhw8diyyzq4TGy9VHTZMYYPVSPAdRRhdtoCAU/Odq/i6e7VXgRgyE9P
+hvwEzPjBjB/M+Mknqb+KZ/WB3f7PjOioUgAzIzAzAjMjjqxeWMXLF4De/uvZdlUSAT3bRisiDmQv
+9Gwzq9NYJRRqAL8Z6Gt1VfwBeS3Ia6G96QkVKauEkhQQOIce02FJHKLKBT2m44AwJwQhGFGIPlTz
+JEC4q4KeZoLAvannqYm3UghcKB1C+qHfu5q1dcEIX9ii9D4BdwfXGa0SCo2Au+4rl8ama9Kf2qUa
+3Hnti+AKHdLzS0Gpo3BnA+1UEAB3BypYR+Wr9wk4SWNOtpqyb7Ihybn3MeqGZFomNFj1jy+T2zuA
+vL7fsN8+UNrgWx+72+NfqlHwdb10tlrcnCwu1pd9u7h9eK1eLZbnn35ffVxebm7c5qt/++3/WNzM
+n/ibS24U3XlKB32fI8HKMbDymMHK7YsNjFH+JNsqoKGqmICsTydriI74HjNZQ/uCCaOUccm3yGeo
+cixg69PZql18ouCY6SqoRzMw2QfxoqR8a3iG2rYJwD4dsKTi8wJHHXFVgiFXNSTAik/0y3f+HQAL
+wO6fSxKfSjJmwAoKlw1MGsS0ltbqfQEkAAvA7k1sReOvPOoArKD8mgkM+bWj0lv9L7oEYoHYfYh1
+0eIBN+rqAUF5O6sY8nZHIbb/hZ5ALBC734u9iLuxF3uHl8pTdjrRY1AUhksLReGelL7HFYXPHOeM
+gJ4qCsPlgqJwX7pfoiPMae8A8/MWTlrfe/y67O/roYRwR+00WWgIj6mbJgPVXZzWBE5rz+Ok5udg
+dQftOYLal2FQrH43W85vbtd3f2vN1iu9eVnR0DNGXd8zjtMMLAPL8pJQB5vQazi4XsMExzPA8eyi
+e1FQ9DcH0XU5zzPIep7D7HccrOsZnnMC0eB+gmg98j9BtIERjSWJFucZaAaaZTYUB3SCvMQA5SUS
+vM+tRQB2v8boDGuGg5wDqp2sBzpMiYvBiuhq9zykYA1+KLDWK08UWAPW6rCWADUgDUjLb+IhEIWe
+tgFqi8XL2RV43YFSWUpBuxoQr6FVBl6D13GpsoSwwdYiMLtfE22HNV1MLmxgSDZsMEy5tMGGDQw9
+T4gcbK8C2AC2fgQPQDaQrY5sCVgD08C0xplmqCPRCU6eBAu24WSerzpYvEETp/IuFHMTWjS7Uhhr
+pkYJmrkgNoidIOaWEnTYXgVut6kJF+d2Z7JwfQ86OGmpkIEq9w427OD4eULkYXsV2Aa29SP4ALaB
+bfVsSwAbqAaqNU41jE7ASRsn7aTJCQnB0Yu9zD49b0k5/Tu49d3d6QzeXc1iQLC0q2kMWYdZy89m
+7Idv7S7SIouAXg+mTyDS+ITT+MVRx/GjJ1Zk7SMDk+UY5UVakBKYBCbzDloCk8DksTPHEhgJQAKQ
++cc/gbux4+6UIqw7pT2gOx3DGGsSG6kYOpmG1sw8RYxDa39SI+ahZZWDOeUYV3nMXJUbVZs8ZjLk
+MKsa834GMwQXZG2IrCFG1jBmsgYxsiYPUhPWfm8GrRB/HxBbof7eEFu1i8FVuzHT9RAFUKnRGsKR
+yWbwCvnPIfEVlZkN8dVEw61m1PFWIxdw7UxeuRnAmtaCrsNUBQFgxxF2jUYHeNThAZaLD+iChgRY
+yHgAsUBsAmJdNLXlRp3bcnLJrc4U8ZpBLPo3gVggNqUoaxWtytpXf3qKhvvGSrzi9abdVHk1MyAE
+RV5tt9mjxiuzYEMU0wxMt18xljLKqZOisa5HJKNmrN91/QB1UyVjUVAHgLr9ArQE8ZOOatC6Ht+E
+ErSeoxoVaI1VoK3iJWiAdS8nWjvGQGtUtEFqcFQFbat4RRtw3ct5W51VyOUxbwtjs8DrAdbHxWPW
+iIX0c2xWZwV3GJuFsVkgdmfldqt4vR2I3cvxC8OaaIvyPRAbxI4S++07rVZ1vL5fsEPrt+/OZWGt
+RcujvTXOsQhmtRIUAArDKeZ4fF9+LN20BMDqFquQQY9G6EExelCW9NBWFcY7mZpdJVpkYNyAqgyo
+DiEEhIwBIRxDCGeJEKN08JZEEMKiCHF+UKG5OoQwEDKGMpz6CpzKWJMsOkgUHTsJuOOPLnICez/Y
+PEShp++Szy91ESKnDkVHqkC9KcvT7/vc3+ezi/3lMYEMc+GSrvTfy8vb+d5LEWvtCYrSO0jOuy2z
+viUzOyhqRSxERTndJp+HnnM6E6kbJjoRJhbeam9dSLpSPROr8AomZl8AWF/7lx0TSXEQYqKgyD1n
+IsWcTkXuhoqaRLCoC6eC8ZoEuFiF2JFwkYfLxUiNXXZcZFVIjXiTk6DLRuIznYuhGy6uDwICXDSF
+IZ3sedZzsZKxIwFjGC4YdSS0qHGMbtVlxDka52ico7vHYn1wURNO0q16jDhJ4ySNk3QvyFgfYtQG
+Z2kJMmYjNYyzNM7SOEvfoTEilJNfmDHoQqxQR25Wm9ap5X0un1Id1w0d14cCATr64NiQDB0rSQs6
+5k7H8/pI43l1pPG8z3j0pW6nNqoYExooOipkbKYFqyMsyviMxlpmI3GYrrzUfg0zp70KRSh9QFrE
+vepLJYC6/KIMTcM9b0zXRz7PKT9May71lPVjOoUfTqNsR8kgGd/Vea1CcJx0pXpKV15qL6ULMqx9
+4UofkFZwr/pSoHTmlK6Pwp6b/ChNodS215PhFDygbuSuklMyWXttmLT3JJG2r77WfgmcwC4YW5jS
+J8Q13Cu/1yhoPeCc2Xl9YPic86O19Ya5fxMqBqWh21HCzMiUEpALbCxbkQbOymvtpbVxhXFeB1/6
+hLiEe+X3GgWtBx2ojkWq9Zi9a+rEu0YQZKBBEO+NKlhrQwFBEARBDgN1JFatacyONXfiWCMMMuQw
+iGWnQyBy1iEMgjDIobyORK11hmFrrdmF0L9IyKC0zhEJOTYSogoy3rE1mhAJQSTkUGBHAtc6w8g1
+kZjwsey8zs7KmoeEbCdTuWeNC14VIn1w1dfai2ynA3tLhXLEjc6zq/xeQHbmooizD5/rvez7FbvY
+fvHZSIN7Kqw5LSccK6ftEPKQjT08hp0yNaghXuxVkz5W/ym6LTjnbSGoeSIok+fy0Dw5PGQ4oI0R
+ohsj5LwxBFUv5Hq7DWWienG4oz+gnaFddGtol/PeENQ9EGzudZyJ8MERbb0D2h0mftAwWZ80BFvf
+jaCOXDYam0nbwwz2vBH3qzhrx0oTBTE9MTnXqrOh7A2dOobqXLn4gdzlfSJ3JkgdyZ2g4F42ulJH
+zMAe0P6g2Soex13t3R/nmWyQ/hUkh+FIZww3lruKB3Oz3huGvJCwjGwrrBtOsf5w47mreEA3683h
+rBVS8xCtt+sqqPvk3TGYaO0qIVybt8tktBJq4pKtXOoqZtuM4tiAQ7arhJht1luEHGmh9hkj2+g4
+qM70IcdtVwmB27xPFxt91dYjtwl7pBjU2P4Bx25XCcHbrPeIJSahrh4n24c5qL6eAcdvP3zmaBku
+78lusPz2MCjFRSnuU7ZGr/UMnlAVzNGq4Kx3KSqDR1YZfPA+Lf+u/m3TEN2mIfdtijrlsdUp92Sf
+snTFNMcrprPeqaiaHl/VdE/2apCu3+Z4/XbWexU13COs4R7gZuW4B8zZu8CoKB9jRfkAd6uLh5Vc
+/nEl1LePsL59gLu1iB9ai+wPrcTWS51aC7lTa6kDIPfdWuDY2vRu3ZSncbwvhvdVHrS3Xb9M3m6+
+fWnRvg3869XtcnZ+flr1Yt09l9++ot1mSO02I5lF+yAzt1+iTmIWrVXGaMcBAv9Pba3ieGsV8IqO
+rd53bI1kgIpW3njWgTgZsEdNUNFKOWeo6LHCcw6lDKt4LQMAi66/ziWbJQmb/+AT0uw5GMdBqUYH
+n5DShpwtaGyUZfFmUE5oBgVn0WOaRY/peEaWMBVcOE+FU6rRkSWsyFmjA48NtUG8qZgTmoqBWvQq
+59GrPJ5ZI8GGojBsi/Tg7HGzRoKyRjnjAlj71OZ0TmhOB2vR855Hz3sqawuZEEIlh45kbfW19sdp
+KQQulA4hPYbg3VHDU0n5whal9xi0PUbmgBNkDkBbqCfkoZ6QHLAlmaTY99yURMi2+mL7geu1L4Ir
+dEjPjK3/7GOAawPtVDgAuAdXYq4SSjEB3F3g+uBK9TitlHcm5MfCkMK2RWNh202jGoAL4LYI3LNo
+C+jZ3g7QM4gUNa9+ApUiqBRdfDiLtpOdce7btJcqRcm1PVApgkrRh7Noj/ZZyH2b9lKlKLkwBCpF
+UCm6+HAWVyk60y73ndpPlaKBKZ9Apqj5I6qKn1HVIA6pQqdUJXhMVUParKR2lmGzym7WuKbYmck+
+otRPTbGB6Z5AU6zxzRrXFDvj7A+sPdUUG5juCTTFGt+tcU2xM5d/FLifmmJWEzTFsFsP2K1xTbGz
+IvsQU081xZwnaIphtx5UBrGK10Gs9u5WiN70TFOso/oKiIpBVAyiYpX1K6t4AQv42qTmDXeieQNR
+MYiKQVSsjcqjVbz0CIBtVOwmdKJ1A1UxqIpBVay9wrFVQuUYQNuo1I2sqtiwpG4gKwZZscHU/a0S
+Cv/A2j1R2farCVPismpArD28nvCQ0KxgbFYJBmdVY6zdMTAHxWfRBvy0ss1VQt0mWNuo0I2shOOw
+hG4g4QgJx8FU3a4Sym7B2kY1bmQlHIelcQMJR0g4DqhqepVQNg3aVrQ5BfZCxQeyEo6dVWNDwhGK
+YgButPB9lVD5DuBW1SJ4R1aGuLIajp1V1EPDEcQFcWuJ+9MJ83JVx9uHFTu0/enkXJa1RrS/yGsn
+1rorXP/qfwgmux6DLOvWy1c/pqryy+T2bjv9Y3HzcMNLL/rWR+7e9S/7tsTD264n02+Ow29f1xeY
+rRY3J4uL9S96u7h9MD2vFsvzT7+vPi4vNzdy8vW4jf721NVu828/39nkb0+FN7m++5MFN7phVk6m
+OEi2Nij5pKlzOGlur/qxVFOV4BLp0fXA8IBhoilCE0254sSFUKrqO77WUDYnS6l5AsqgM1FTDVAI
+QEn1+wcCFIocQx5W5IgUydgMiZ5FmIdUv0xcwxQeBVMOPPAMGylRogAoAAqAAiclbcDYSf2h59vP
+dweL9TtOKjg7Q/S4k8qRdIzsvMqQYelYhoWd9ioUofQByLD0TyXg9IQj9OMc6Sc4koR7LkLVHf6g
+klKNv4IMa1+40gdGKJLSf/69PXGrD59N7YnyYcnumfLk/LOR5qC0IicSaUikIZHWapDqRFOcKpqy
+xgoSakioIVbVNljW6bIoWb4vyhMtiIMjDo7EWvtoSSELwAKwACxwWtLJ8u5lfY3yt5/vEOXdy16H
+mEcVVmk8yJywx3e+Q37zSXTtJn99//I+baMftKWng0DMG1fbXHr34x3AvDmXbyrdUxT0van0YUFX
+Hfy7/Yg9aeHXpa9VQ64dJexN+uZhay+W6w+tXlxdLf79aL+G3Fr/X5yUVG5Vkzk3CRkWE4JTQUnI
+C1Zfa3+PqtHBWi5sEXxq3QEf16OqOGymnKFHtUEvldoyHdcR23EN49F/4+EtjAeMx9HGwwXttDaq
+CN7CeMB4JBqPdQa0znjc/RzGY/59jrMSShmLijVS82ePHIbylO5qewaErIwF0c57T9qJmJA9F9s/
+t8cZr1VR6MIzNyqSQ6rw2hJsyEBsyHXMiOAIkoMVMQFWBFYEVgRWpH0rEp7TdW2a9WEF7MjDFC/V
+vyGfpMMP3h4zSvkAI5LZhPt6C/Jutpzf3K4f3NaarY1xvWpM2lPI3NxHhESEPauuVTMmRLP35Fx6
+q6Y9Kui1Gak7QktDQ7Q0HLU0DEsDS5OHpWFYGlgaWJpeWpqYnYGVgZXJw8p0FBGD3YDd6FEbdlsZ
+FRc9ojwsgfWY38/+FMqpiHYzsPE/pFqPHXGdRPNBYzqkaNfcKYVkrM1DXkLA2lRfq2bOjWJFgTy7
+EPbZkJiNTUu+ONLMOKcMxN6EuL3BaQX2Jhd7E2BvYG9gb3prb6LWBrYGtiYTW6NdurGB9YD1QKXY
+06yHoehp5WEJLMh3LX0ZE2Jk1fSTUyuGjjMgPKbDiqHmDissY25YF2wUi5ib6mvtNTeGVVDWWS6M
+d4nmho4yN5aYQoC5GYi5iZ5Xvq+BwYHBGdSJBRYHFgcWp2WLcxUzN1ewNf23NSWl1nHaGkPdRMdg
+PGA8xmk8omcVHFRwUIHxgPGA8YDxKBuP25jxuIXx6L/x0AXBeMB4wHjAeLQpRBxPlHxfAxMyf5jz
+DEHiEWRKHDeXKYF+MfSLx2pzogGv72tgc2BzhnVugc2BzYHNadvmXMUMDtLzGVgbmzxoEBNXYD1g
+PWA9hMZ1xawHzio4q8B8wHzAfMB87JqPmPWA8cjAeGBUMIwHjAeMR+uTuuLpkq1FMCN3G6dwGNg1
+kozJelZdcyr4bgDzvbRxhoicLrxVGPAF43OA8YkGv7YWwfjA+MD4wPjA+MD4iMwojloemB2YHQwp
+hh2BHYEd2W9HomYEViQDK0IKVgRWBFYEVqR9K/LTS6eWtWbkYcWOHfnppbAhqTAgTyC+1y5IZdyV
+KO/J/GBT633709zerw2+s3Wrd3j56j0acKEn043rZFrZ6G9PWa0+fDZ1W31rzc5mf3t6/tnIbnct
+ut1N4YyS8vBYdMM7f5wERk/n5z26Nz/WzLvd46TpA6mys9VTsLLzoU4dB13Lldf3u+RpbDmIItNB
+UO1FqOPZi1BFshfhwwutpY/AsijT2oW250/HWpRcNwzb2WoxiE3fSQw7ILK5owwu0oEwKWphUowe
+JoeM7IpVHR/Z8NhnnGgHnhzOEx4uT7SqBYpWoycK3BO4JxnPKG2bJ7qeJxoeCjwUeCjiSOFBR4WX
+qw+fKRYWvl9UGRemPqeBqHCFaz8sLBcTDggJDy/RFEYTEL6D2XSi2wHae56tPnzmWp59X7OLs/fn
+n7nPOCtUyTE5HmaiXeQ8IJrVwEwfWoQ0hrIYAswagllIgFnIF2Y6KCJuO5KUUKETEmmWQ11lAM4k
+j3vA2fE40y6BZ9rlCzTjFHP7oawEgVE3oAok7YA0ycJlIO14pBlKQJqhfJHmSYXC9XGwmB5SVaUh
+MA1Mk2bab1//3wDS7QZ7IMEHAA==
+`
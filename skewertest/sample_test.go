@@ -0,0 +1,66 @@
+package skewertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexeldeib/skewer"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_SampleSKUs(t *testing.T) {
+	client, err := skewer.NewFileClient(eastusPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := client.List(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skus := SampleSKUs()
+	if diff := cmp.Diff(expected, skus); diff != "" {
+		t.Errorf("expected sample dataset to match testdata: %s", diff)
+	}
+
+	name := "modified"
+	skus[0].Name = &name
+	if diff := cmp.Diff(expected, SampleSKUs()); diff != "" {
+		t.Errorf("expected each call to return a new copy: %s", diff)
+	}
+}
+
+func Test_NewSampleCache(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewSampleCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+		t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+	}
+	if _, found := cache.Get(ctx, "standard_d4s_v3", skewer.VirtualMachines); !found {
+		t.Errorf("expected to find virtual machine sku standard_d4s_v3")
+	}
+}
+
+func Test_NewSampleResourceClient(t *testing.T) {
+	ctx := context.Background()
+	client := NewSampleResourceClient()
+
+	iter, err := skewer.NewSKUIterator(ctx, client, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sku, found, err := iter.Find(ctx, skewer.NameFilter("standard_d4s_v3"))
+	if err != nil || !found {
+		t.Fatalf("expected to find standard_d4s_v3, found %t with error: %v", found, err)
+	}
+	if got := sku.GetName(); got != "Standard_D4s_v3" {
+		t.Errorf("expected name Standard_D4s_v3 but found %s", got)
+	}
+	if got := client.PageCalls(); got >= 5 {
+		t.Errorf("expected lookup to stop before the last page, fetched %d pages", got)
+	}
+}
@@ -0,0 +1,256 @@
+// Package skewertest provides a fake Azure Resource SKUs API for tests.
+// It allows exercising the real compute.ResourceSkusClient, and skewer
+// on top of it, end to end without network access.
+package skewertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/alexeldeib/skewer"
+)
+
+// DefaultPageSize is the number of skus served per page unless
+// overridden with WithPageSize.
+const DefaultPageSize = 100
+
+// skusPath matches the Resource SKUs list operation.
+var skusPath = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/providers/Microsoft\.Compute/skus/?$`)
+
+// Source supplies the skus served by a Server. It must evaluate the
+// $filter query parameter, returning an error for filters it does not
+// support. *skewer.FileClient satisfies Source.
+type Source interface {
+	List(ctx context.Context, filter string) ([]compute.ResourceSku, error)
+}
+
+// Fault describes a single injected response.
+type Fault struct {
+	// StatusCode is the status of the response. Zero serves the
+	// request normally after Latency.
+	StatusCode int
+	// RetryAfter sets the Retry-After header, in whole seconds, when
+	// greater than zero.
+	RetryAfter time.Duration
+	// Latency delays the response, in addition to any latency
+	// configured with WithLatency.
+	Latency time.Duration
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithPageSize sets the number of skus served per page.
+func WithPageSize(size int) ServerOption {
+	return func(s *Server) {
+		if size > 0 {
+			s.pageSize = size
+		}
+	}
+}
+
+// WithLatency delays every response by latency.
+func WithLatency(latency time.Duration) ServerOption {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// Server is a fake Resource SKUs API serving
+// GET /subscriptions/{id}/providers/Microsoft.Compute/skus. Results are
+// paged with nextLink, and responses may be delayed or replaced with
+// errors to simulate throttling and outages. Close must be called when
+// the server is no longer needed.
+type Server struct {
+	*httptest.Server
+	source   Source
+	pageSize int
+	latency  time.Duration
+
+	mu         sync.Mutex
+	faults     []Fault
+	pageFaults map[int][]Fault
+}
+
+// NewServer starts a Server serving skus from source.
+func NewServer(source Source, opts ...ServerOption) *Server {
+	s := &Server{
+		source:     source,
+		pageSize:   DefaultPageSize,
+		pageFaults: map[int][]Fault{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewServerFromFiles starts a Server serving Resource SKUs list
+// responses read from paths, as accepted by skewer.NewFileClient.
+func NewServerFromFiles(paths []string, opts ...ServerOption) (*Server, error) {
+	source, err := skewer.NewFileClient(paths...)
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(source, opts...), nil
+}
+
+// Client returns a compute.ResourceSkusClient for subscriptionID which
+// sends requests to the server. The autorest retry decorators are
+// disabled, so injected faults reach the caller and any retries are
+// skewer's own.
+func (s *Server) Client(subscriptionID string) compute.ResourceSkusClient {
+	client := compute.NewResourceSkusClientWithBaseURI(s.URL, subscriptionID)
+	client.Authorizer = autorest.NullAuthorizer{}
+	client.SendDecorators = []autorest.SendDecorator{}
+	return client
+}
+
+// InjectFaults queues faults for the next requests, one request per
+// fault, regardless of which page is requested.
+func (s *Server) InjectFaults(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, faults...)
+}
+
+// InjectPageFaults queues faults for the next requests for the
+// zero-based page, one request per fault. Faults queued with
+// InjectFaults take precedence.
+func (s *Server) InjectPageFaults(page int, faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageFaults[page] = append(s.pageFaults[page], faults...)
+}
+
+// nextFault removes and returns the fault for a request for page.
+func (s *Server) nextFault(page int) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.faults) > 0 {
+		fault := s.faults[0]
+		s.faults = s.faults[1:]
+		return fault, true
+	}
+	if faults := s.pageFaults[page]; len(faults) > 0 {
+		s.pageFaults[page] = faults[1:]
+		return faults[0], true
+	}
+	return Fault{}, false
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", r.Method))
+		return
+	}
+	if !skusPath.MatchString(r.URL.Path) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no route for path '%s'", r.URL.Path))
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("api-version") == "" {
+		writeError(w, http.StatusBadRequest, "the api-version query parameter is required")
+		return
+	}
+
+	page := 0
+	if token := query.Get("$skiptoken"); token != "" {
+		var err error
+		page, err = strconv.Atoi(token)
+		if err != nil || page < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid $skiptoken '%s'", token))
+			return
+		}
+	}
+
+	fault, faulted := s.nextFault(page)
+	if !sleep(r.Context(), s.latency+fault.Latency) {
+		return
+	}
+	if faulted && fault.StatusCode != 0 {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(fault.RetryAfter/time.Second)))
+		}
+		writeError(w, fault.StatusCode, fmt.Sprintf("injected fault for page %d", page))
+		return
+	}
+
+	skus, err := s.source.List(r.Context(), query.Get("$filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start := page * s.pageSize
+	if start > len(skus) {
+		start = len(skus)
+	}
+	end := start + s.pageSize
+	if end > len(skus) {
+		end = len(skus)
+	}
+
+	values := skus[start:end]
+	result := compute.ResourceSkusResult{Value: &values}
+	if end < len(skus) {
+		next := s.nextLink(r, page+1)
+		result.NextLink = &next
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// nextLink returns an absolute link to page, preserving the query of r.
+func (s *Server) nextLink(r *http.Request, page int) string {
+	query := r.URL.Query()
+	query.Set("$skiptoken", strconv.Itoa(page))
+	link := url.URL{
+		Path:     r.URL.Path,
+		RawQuery: query.Encode(),
+	}
+	return s.URL + link.String()
+}
+
+// sleep waits for d, returning false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// writeError writes an Azure Resource Manager error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	body := map[string]interface{}{
+		"error": map[string]string{
+			"code":    strings.ReplaceAll(http.StatusText(status), " ", ""),
+			"message": message,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,197 @@
+package skewertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/alexeldeib/skewer"
+)
+
+const (
+	eastusPath                   = "../testdata/eastus.json"
+	expectedVirtualMachinesCount = 377
+)
+
+func newEastUSServer(t *testing.T, opts ...ServerOption) *Server {
+	server, err := NewServerFromFiles([]string{eastusPath}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func Test_Server_EndToEnd(t *testing.T) {
+	ctx := context.Background()
+	server := newEastUSServer(t, WithPageSize(50))
+	defer server.Close()
+
+	cases := map[string]struct {
+		location string
+		expected int
+	}{
+		"should serve location": {
+			location: "eastus",
+			expected: expectedVirtualMachinesCount,
+		},
+		"should match location case-insensitively": {
+			location: "EastUS",
+			expected: expectedVirtualMachinesCount,
+		},
+		"should serve nothing for other locations": {
+			location: "westus",
+			expected: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			cache, err := skewer.NewCache(ctx, skewer.WithResourceClient(server.Client("subscription")), skewer.WithLocation(tc.location))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(cache.GetVirtualMachines(ctx)); got != tc.expected {
+				t.Errorf("expected %d virtual machine skus but found %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func Test_Server_Pages(t *testing.T) {
+	ctx := context.Background()
+	server := newEastUSServer(t, WithPageSize(100))
+	defer server.Close()
+
+	iter, err := skewer.NewSKUIterator(ctx, server.Client("subscription"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, pages := 0, map[int]bool{}
+	for iter.NotDone() {
+		count++
+		pages[iter.Page()] = true
+		if err := iter.NextWithContext(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count != 436 {
+		t.Errorf("expected 436 skus but found %d", count)
+	}
+	if len(pages) != 5 {
+		t.Errorf("expected 5 pages but found %d", len(pages))
+	}
+}
+
+func Test_Server_Faults(t *testing.T) {
+	ctx := context.Background()
+	policy := skewer.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	cases := map[string]struct {
+		faults     []Fault
+		page       int
+		retry      bool
+		stage      skewer.ListStage
+		statusCode int
+	}{
+		"should fail the first page": {
+			faults:     []Fault{{StatusCode: http.StatusInternalServerError}},
+			page:       0,
+			stage:      skewer.StageList,
+			statusCode: http.StatusInternalServerError,
+		},
+		"should fail a later page": {
+			faults:     []Fault{{StatusCode: http.StatusTooManyRequests}},
+			page:       2,
+			stage:      skewer.StageNextPage,
+			statusCode: http.StatusTooManyRequests,
+		},
+		"should recover with retries": {
+			faults: []Fault{{StatusCode: http.StatusTooManyRequests}, {StatusCode: http.StatusInternalServerError}},
+			page:   2,
+			retry:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			server := newEastUSServer(t, WithPageSize(50))
+			defer server.Close()
+			server.InjectPageFaults(tc.page, tc.faults...)
+
+			opts := []skewer.CacheOption{skewer.WithResourceClient(server.Client("subscription")), skewer.WithLocation("eastus")}
+			if tc.retry {
+				opts = append(opts, skewer.WithRetryPolicy(policy))
+			}
+			cache, err := skewer.NewCache(ctx, opts...)
+
+			if tc.statusCode == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := len(cache.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+					t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+				}
+				return
+			}
+
+			errList := &skewer.ErrListResourceSkus{}
+			if !errors.As(err, &errList) || errList.Stage != tc.stage || errList.Page != tc.page {
+				t.Fatalf("expected failure at stage '%s' page %d, got: %v", tc.stage, tc.page, err)
+			}
+			detailed := autorest.DetailedError{}
+			if !errors.As(err, &detailed) || detailed.StatusCode != tc.statusCode {
+				t.Errorf("expected status code %d, got: %v", tc.statusCode, err)
+			}
+		})
+	}
+}
+
+func Test_Server_RetryAfter(t *testing.T) {
+	server := newEastUSServer(t)
+	defer server.Close()
+	server.InjectFaults(Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: 3 * time.Second})
+
+	_, err := server.Client("subscription").List(context.Background(), "")
+	detailed := autorest.DetailedError{}
+	if !errors.As(err, &detailed) || detailed.Response == nil {
+		t.Fatalf("expected detailed error with response, got: %v", err)
+	}
+	if got := detailed.Response.Header.Get("Retry-After"); got != "3" {
+		t.Errorf("expected Retry-After header '3', got '%s'", got)
+	}
+}
+
+func Test_Server_Latency(t *testing.T) {
+	server := newEastUSServer(t, WithLatency(time.Second))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := server.Client("subscription").List(ctx, "")
+	if err == nil {
+		t.Fatal("expected request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected request to be cancelled promptly, took %s", elapsed)
+	}
+}
+
+func Test_Server_UnsupportedFilter(t *testing.T) {
+	server := newEastUSServer(t)
+	defer server.Close()
+
+	_, err := server.Client("subscription").List(context.Background(), "name eq 'Standard_D4s_v3'")
+	detailed := autorest.DetailedError{}
+	if !errors.As(err, &detailed) || detailed.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got: %v", http.StatusBadRequest, err)
+	}
+}
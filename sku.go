@@ -2,11 +2,9 @@ package skewer
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
-	"github.com/pkg/errors"
 )
 
 // SKU wraps an Azure compute SKU with richer functionality
@@ -58,6 +56,89 @@ const (
 	// CachedDiskBytes identifies the maximum size of the cach disk for
 	// a vm.
 	CachedDiskBytes = "CachedDiskBytes"
+	// VCPUsAvailable identifies the number of vCPUs available to the
+	// guest, which is lower than VCPUs for constrained vCPU sizes.
+	VCPUsAvailable = "vCPUsAvailable"
+	// VCPUsPerCore identifies the number of vCPUs per physical core.
+	VCPUsPerCore = "vCPUsPerCore"
+	// MaxDataDiskCount identifies the maximum number of data disks.
+	MaxDataDiskCount = "MaxDataDiskCount"
+	// MaxNetworkInterfaces identifies the maximum number of network
+	// interfaces.
+	MaxNetworkInterfaces = "MaxNetworkInterfaces"
+	// MaxResourceVolumeMB identifies the size of the temporary disk.
+	MaxResourceVolumeMB = "MaxResourceVolumeMB"
+	// OSVhdSizeMB identifies the maximum size of the os disk.
+	OSVhdSizeMB = "OSVhdSizeMB"
+	// UncachedDiskIOPS identifies the maximum uncached disk iops.
+	UncachedDiskIOPS = "UncachedDiskIOPS"
+	// UncachedDiskBytesPerSecond identifies the maximum uncached disk
+	// throughput.
+	UncachedDiskBytesPerSecond = "UncachedDiskBytesPerSecond"
+	// CombinedTempDiskAndCachedIOPS identifies the maximum iops of the
+	// temporary disk and cached disks combined.
+	CombinedTempDiskAndCachedIOPS = "CombinedTempDiskAndCachedIOPS"
+	// CombinedTempDiskAndCachedReadBytesPerSecond identifies the maximum
+	// read throughput of the temporary disk and cached disks combined.
+	CombinedTempDiskAndCachedReadBytesPerSecond = "CombinedTempDiskAndCachedReadBytesPerSecond"
+	// CombinedTempDiskAndCachedWriteBytesPerSecond identifies the maximum
+	// write throughput of the temporary disk and cached disks combined.
+	CombinedTempDiskAndCachedWriteBytesPerSecond = "CombinedTempDiskAndCachedWriteBytesPerSecond"
+	// ACUs identifies the azure compute units per vCPU.
+	ACUs = "ACUs"
+	// GPUs identifies the number of gpus.
+	GPUs = "GPUs"
+	// PremiumIO identifies the capability for premium storage support.
+	PremiumIO = "PremiumIO"
+	// LowPriorityCapable identifies the capability for spot and low
+	// priority support.
+	LowPriorityCapable = "LowPriorityCapable"
+	// RdmaEnabled identifies the capability for rdma support.
+	RdmaEnabled = "RdmaEnabled"
+)
+
+const (
+	// MinSizeGiB identifies the minimum size of a disk sku.
+	MinSizeGiB = "MinSizeGiB"
+	// MaxSizeGiB identifies the maximum size of a disk sku.
+	MaxSizeGiB = "MaxSizeGiB"
+	// MinIOps identifies the minimum provisioned iops of a disk sku.
+	MinIOps = "MinIOps"
+	// MaxIOps identifies the maximum provisioned iops of a disk sku.
+	MaxIOps = "MaxIOps"
+	// MinBandwidthMBps identifies the minimum provisioned throughput of
+	// a disk sku.
+	MinBandwidthMBps = "MinBandwidthMBps"
+	// MaxBandwidthMBps identifies the maximum provisioned throughput of
+	// a disk sku.
+	MaxBandwidthMBps = "MaxBandwidthMBps"
+	// MinIOpsReadWrite identifies the minimum iops of an ultra disk.
+	MinIOpsReadWrite = "MinIOpsReadWrite"
+	// MaxIOpsReadWrite identifies the maximum iops of an ultra disk.
+	MaxIOpsReadWrite = "MaxIOpsReadWrite"
+	// MinBandwidthMBpsReadWrite identifies the minimum throughput of an
+	// ultra disk.
+	MinBandwidthMBpsReadWrite = "MinBandwidthMBpsReadWrite"
+	// MaxBandwidthMBpsReadWrite identifies the maximum throughput of an
+	// ultra disk.
+	MaxBandwidthMBpsReadWrite = "MaxBandwidthMBpsReadWrite"
+	// MaxValueOfMaxShares identifies the maximum number of vms a disk
+	// may be attached to at once.
+	MaxValueOfMaxShares = "MaxValueOfMaxShares"
+	// MaxBurstIops identifies the iops a disk may burst to.
+	MaxBurstIops = "MaxBurstIops"
+	// MaxBurstBandwidthMBps identifies the throughput a disk may burst
+	// to.
+	MaxBurstBandwidthMBps = "MaxBurstBandwidthMBps"
+	// MaxBurstDurationInMin identifies how long a disk may burst with a
+	// full credit bucket.
+	MaxBurstDurationInMin = "MaxBurstDurationInMin"
+	// BurstCreditBucketSizeInIO identifies the size of the burst credit
+	// bucket in io operations.
+	BurstCreditBucketSizeInIO = "BurstCreditBucketSizeInIO"
+	// BurstCreditBucketSizeInGiB identifies the size of the burst credit
+	// bucket in GiB.
+	BurstCreditBucketSizeInGiB = "BurstCreditBucketSizeInGiB"
 )
 
 // ErrCapabilityNotFound will be returned when a capability could not be
@@ -92,6 +173,17 @@ func (e *ErrCapabilityValueParse) Error() string {
 	return fmt.Sprintf("%sCapabilityValueParse: failed to parse string '%s' as int64, error: '%s'", e.capability, e.value, e.err)
 }
 
+// ErrResourceType will be returned when a sku is converted to a view of
+// another resource type.
+type ErrResourceType struct {
+	expected string
+	actual   string
+}
+
+func (e *ErrResourceType) Error() string {
+	return fmt.Sprintf("expected sku of resource type '%s', found '%s'", e.expected, e.actual)
+}
+
 // VCPU returns the number of vCPUs this SKU supports.
 func (s *SKU) VCPU() (int64, error) {
 	return s.GetCapabilityIntegerQuantity(VCPUs)
@@ -119,49 +211,21 @@ func (s *SKU) IsEphemeralOSDiskSupported() bool {
 }
 
 // GetCapabilityIntegerQuantity retrieves and parses the value of an
-// integer numeric capability with the provided name. It errors if the
-// capability is not found, the value was nil, or the value could not be
-// parsed as an integer.
+// integer numeric capability with the provided name, ignoring case. It
+// errors if the capability is not found, the value was nil, or the
+// value could not be parsed as an integer.
 func (s *SKU) GetCapabilityIntegerQuantity(name string) (int64, error) {
-	if s.Capabilities == nil {
-		return -1, &ErrCapabilityNotFound{name}
-	}
-	for _, capability := range *s.Capabilities {
-		if capability.Name != nil && *capability.Name == name {
-			if capability.Value != nil {
-				intVal, err := strconv.ParseInt(*capability.Value, 10, 64)
-				if err != nil {
-					return -1, &ErrCapabilityValueParse{name, *capability.Value, err}
-				}
-				return intVal, nil
-			}
-			return -1, &ErrCapabilityValueNil{name}
-		}
-	}
-	return -1, &ErrCapabilityNotFound{name}
+	capability, ok := lookupCapability(s.Capabilities, name)
+	return capabilityInteger(capability, ok, name)
 }
 
 // GetCapabilityFloatQuantity retrieves and parses the value of a
-// floating point numeric capability with the provided name. It errors
-// if the capability is not found, the value was nil, or the value could
-// not be parsed as an integer.
+// floating point numeric capability with the provided name, ignoring
+// case. It errors if the capability is not found, the value was nil, or
+// the value could not be parsed as an integer.
 func (s *SKU) GetCapabilityFloatQuantity(name string) (float64, error) {
-	if s.Capabilities == nil {
-		return -1, &ErrCapabilityNotFound{name}
-	}
-	for _, capability := range *s.Capabilities {
-		if capability.Name != nil && *capability.Name == name {
-			if capability.Value != nil {
-				intVal, err := strconv.ParseFloat(*capability.Value, 64)
-				if err != nil {
-					return -1, &ErrCapabilityValueParse{name, *capability.Value, err}
-				}
-				return intVal, nil
-			}
-			return -1, &ErrCapabilityValueNil{name}
-		}
-	}
-	return -1, &ErrCapabilityNotFound{name}
+	capability, ok := lookupCapability(s.Capabilities, name)
+	return capabilityFloat(capability, ok, name)
 }
 
 // HasCapability return true for a capability which can be either
@@ -169,15 +233,8 @@ func (s *SKU) GetCapabilityFloatQuantity(name string) (float64, error) {
 // "EncryptionAtHostSupported", "AcceleratedNetworkingEnabled", and
 // "RdmaEnabled"
 func (s *SKU) HasCapability(name string) bool {
-	if s.Capabilities == nil {
-		return false
-	}
-	for _, capability := range *s.Capabilities {
-		if capability.Name != nil && strings.EqualFold(*capability.Name, name) {
-			return capability.Value != nil && strings.EqualFold(*capability.Value, string(CapabilitySupported))
-		}
-	}
-	return false
+	capability, ok := lookupCapability(s.Capabilities, name)
+	return ok && capability.flags&capabilityIsSupported != 0
 }
 
 // HasZonalCapability return true for a capability which can be either
@@ -198,15 +255,9 @@ func (s *SKU) HasZonalCapability(name string) bool {
 			continue
 		}
 		for _, zoneDetails := range *locationInfo.ZoneDetails {
-			if zoneDetails.Capabilities == nil {
-				continue
-			}
-			for _, capability := range *zoneDetails.Capabilities {
-				if capability.Name != nil && strings.EqualFold(*capability.Name, name) {
-					if capability.Value != nil && strings.EqualFold(*capability.Value, string(CapabilitySupported)) {
-						return true
-					}
-				}
+			capability, ok := lookupCapability(zoneDetails.Capabilities, name)
+			if ok && capability.flags&capabilityIsSupported != 0 {
+				return true
 			}
 		}
 	}
@@ -218,15 +269,8 @@ func (s *SKU) HasZonalCapability(name string) bool {
 // the desired substring. An example is "HyperVGenerations" which may be
 // "V1,V2"
 func (s *SKU) HasCapabilityWithSeparator(name, value string) bool {
-	if s.Capabilities == nil {
-		return false
-	}
-	for _, capability := range *s.Capabilities {
-		if capability.Name != nil && strings.EqualFold(*capability.Name, name) {
-			return capability.Value != nil && strings.Contains(*capability.Value, value)
-		}
-	}
-	return false
+	capability, ok := lookupCapability(s.Capabilities, name)
+	return capabilityContains(capability, ok, value)
 }
 
 // HasCapabilityWithCapacity returns true when the provided resource
@@ -238,24 +282,8 @@ func (s *SKU) HasCapabilityWithSeparator(name, value string) bool {
 // "CombinedTempDiskAndCachedWriteBytesPerSecond", "UncachedDiskIOPS",
 // and "UncachedDiskBytesPerSecond"
 func (s *SKU) HasCapabilityWithCapacity(name string, value int64) (bool, error) {
-	if s.Capabilities == nil {
-		return false, nil
-	}
-	for _, capability := range *s.Capabilities {
-		if capability.Name != nil && strings.EqualFold(*capability.Name, name) {
-			if capability.Value != nil {
-				intVal, err := strconv.ParseInt(*capability.Value, 10, 64)
-				if err != nil {
-					return false, errors.Wrapf(err, "failed to parse string '%s' as int64", *capability.Value)
-				}
-				if intVal >= value {
-					return true, nil
-				}
-			}
-			return false, nil
-		}
-	}
-	return false, nil
+	capability, ok := lookupCapability(s.Capabilities, name)
+	return capabilityAtLeast(capability, ok, value)
 }
 
 // IsAvailable returns true when the requested location matches one on
@@ -0,0 +1,93 @@
+package skewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// Cache.Save. NewCacheFromSnapshot rejects other versions. Version 2
+// added Incomplete and MissingFromPage, which version 1 readers would
+// ignore, loading partial data as complete.
+const SnapshotVersion = 2
+
+// Snapshot is the serialized form of a Cache written by Save.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	Location  string    `json:"location,omitempty"`
+	Filter    string    `json:"filter,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	SKUs      []SKU     `json:"skus"`
+	// Incomplete is true when the skus came from an interrupted listing.
+	// MissingFromPage then holds the first page which failed to load.
+	Incomplete      bool `json:"incomplete,omitempty"`
+	MissingFromPage int  `json:"missingFromPage,omitempty"`
+}
+
+// ErrSnapshotVersion will be returned when a snapshot was written with
+// an unsupported format version.
+type ErrSnapshotVersion struct {
+	version int
+}
+
+func (e *ErrSnapshotVersion) Error() string {
+	return fmt.Sprintf("unsupported snapshot version %d, expected %d", e.version, SnapshotVersion)
+}
+
+// Save writes the cache contents to w as a versioned JSON snapshot,
+// which may be loaded with NewCacheFromSnapshot.
+func (c *Cache) Save(w io.Writer) error {
+	c.mu.RLock()
+	snapshot := Snapshot{
+		Version:   SnapshotVersion,
+		Location:  c.location,
+		Filter:    c.filter,
+		FetchedAt: c.fetchedAt,
+		SKUs:      c.data,
+	}
+	if c.incomplete != nil {
+		snapshot.Incomplete = true
+		snapshot.MissingFromPage = c.incomplete.MissingFromPage
+	}
+	c.mu.RUnlock()
+
+	return json.NewEncoder(w).Encode(snapshot)
+}
+
+// NewCacheFromSnapshot initializes a cache from a snapshot written by
+// Save, without contacting Azure. The location, filter and fetch time
+// are restored from the snapshot. Options are applied afterwards, so a
+// client option may be used to allow later refreshes, and WithTTL
+// treats the snapshot's fetch time as the age of the data.
+func NewCacheFromSnapshot(r io.Reader, opts ...CacheOption) (*Cache, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+
+	if snapshot.Version != SnapshotVersion {
+		return nil, &ErrSnapshotVersion{snapshot.Version}
+	}
+
+	c := &Cache{
+		location:  snapshot.Location,
+		filter:    snapshot.Filter,
+		fetchedAt: snapshot.FetchedAt,
+	}
+
+	if snapshot.Incomplete {
+		c.incomplete = &Completeness{
+			MissingFromPage: snapshot.MissingFromPage,
+		}
+	}
+
+	if err := c.applyOptions(opts); err != nil {
+		return nil, err
+	}
+
+	c.replaceData(c.prepareData(snapshot.SKUs))
+
+	return c, nil
+}
@@ -0,0 +1,86 @@
+package skewer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Cache_Snapshot(t *testing.T) {
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	cache, err := NewCache(ctx, WithClient(&fakeClient{skus: dataWrapper.Value}), WithLocation("eastus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := cache.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should round trip", func(t *testing.T) {
+		loaded, err := NewCacheFromSnapshot(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !loaded.Equal(cache) {
+			t.Errorf("expected loaded cache to have location and filter of saved cache")
+		}
+		if !loaded.FetchedAt().Equal(cache.FetchedAt()) {
+			t.Errorf("expected fetch time %s, got %s", cache.FetchedAt(), loaded.FetchedAt())
+		}
+		if got := len(loaded.GetVirtualMachines(ctx)); got != expectedVirtualMachinesCount {
+			t.Errorf("expected %d virtual machine skus but found %d", expectedVirtualMachinesCount, got)
+		}
+		sku, found := loaded.Get(ctx, "standard_d4s_v3", VirtualMachines)
+		if !found {
+			t.Fatalf("expected to find virtual machine sku standard_d4s_v3")
+		}
+		if cpu, err := sku.VCPU(); cpu != 4 || err != nil {
+			t.Errorf("expected standard_d4s_v3 to have 4 vCPUs and parse successfully, got value '%d' and error '%s'", cpu, err)
+		}
+		if !sku.HasZonalCapability(UltraSSDAvailable) {
+			t.Errorf("expected standard_d4s_v3 to support ultra ssd")
+		}
+	})
+
+	t.Run("should be stale with ttl", func(t *testing.T) {
+		loaded, err := NewCacheFromSnapshot(bytes.NewReader(buf.Bytes()), WithTTL(time.Nanosecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !loaded.IsStale() {
+			t.Errorf("expected loaded cache to be stale")
+		}
+	})
+
+	t.Run("should reject unknown version", func(t *testing.T) {
+		_, err := NewCacheFromSnapshot(strings.NewReader(`{"version": 3, "skus": []}`))
+		errVersion := &ErrSnapshotVersion{}
+		if !errors.As(err, &errVersion) {
+			t.Errorf("expected ErrSnapshotVersion, got: %v", err)
+		}
+	})
+
+	t.Run("should reject version 1, which has no completeness", func(t *testing.T) {
+		_, err := NewCacheFromSnapshot(strings.NewReader(`{"version": 1, "skus": []}`))
+		errVersion := &ErrSnapshotVersion{}
+		if !errors.As(err, &errVersion) {
+			t.Errorf("expected ErrSnapshotVersion, got: %v", err)
+		}
+	})
+
+	t.Run("should reject malformed input", func(t *testing.T) {
+		if _, err := NewCacheFromSnapshot(strings.NewReader(`{`)); err == nil {
+			t.Errorf("expected malformed snapshot to fail")
+		}
+	})
+}
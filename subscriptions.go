@@ -0,0 +1,354 @@
+package skewer
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+// MultiSubscriptionCache aggregates sku data across subscriptions.
+// Restrictions such as NotAvailableForSubscription depend on the
+// subscription used to list skus, so it holds one lazily loaded
+// MultiLocationCache per subscription ID. All methods are safe for
+// concurrent use.
+type MultiSubscriptionCache struct {
+	caches map[string]*MultiLocationCache
+}
+
+// NewMultiSubscriptionCache creates a cache backed by one ResourceClient
+// per subscription ID. The provided options are applied to each
+// subscription's caches, so they must not include a client option or
+// WithLocation.
+func NewMultiSubscriptionCache(clients map[string]ResourceClient, opts ...CacheOption) (*MultiSubscriptionCache, error) {
+	caches := make(map[string]*MultiLocationCache, len(clients))
+	for subscriptionID, client := range clients {
+		subscriptionOpts := append(append([]CacheOption{}, opts...), WithResourceClient(client))
+		cache, err := NewMultiLocationCache(subscriptionOpts...)
+		if err != nil {
+			return nil, err
+		}
+		caches[subscriptionID] = cache
+	}
+
+	return &MultiSubscriptionCache{
+		caches: caches,
+	}, nil
+}
+
+// Subscriptions returns the sorted list of subscription IDs in this cache.
+func (m *MultiSubscriptionCache) Subscriptions() []string {
+	subscriptions := make([]string, 0, len(m.caches))
+	for subscriptionID := range m.caches {
+		subscriptions = append(subscriptions, subscriptionID)
+	}
+	sort.Strings(subscriptions)
+	return subscriptions
+}
+
+// Subscription returns the cache for a single subscription, or false if
+// the subscription is unknown.
+func (m *MultiSubscriptionCache) Subscription(subscriptionID string) (*MultiLocationCache, bool) {
+	cache, ok := m.caches[subscriptionID]
+	return cache, ok
+}
+
+// Preload loads the provided locations for every subscription.
+func (m *MultiSubscriptionCache) Preload(ctx context.Context, locations ...string) error {
+	for _, subscriptionID := range m.Subscriptions() {
+		if err := m.caches[subscriptionID].Preload(ctx, locations...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the first matching resource of a given name and type in a
+// location, as seen by each subscription. Subscriptions which do not
+// list the sku are omitted from the result.
+func (m *MultiSubscriptionCache) Get(ctx context.Context, location, name, resourceType string) (map[string]SKU, error) {
+	result := make(map[string]SKU, len(m.caches))
+	for _, subscriptionID := range m.Subscriptions() {
+		sku, found, err := m.caches[subscriptionID].Get(ctx, location, name, resourceType)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result[subscriptionID] = sku
+		}
+	}
+	return result, nil
+}
+
+// SubscriptionsFor returns the sorted subscription IDs which can deploy
+// a sku in a location. When zone is empty, a subscription qualifies if
+// the sku is available and unrestricted in the location; otherwise the
+// zone must be available and unrestricted too.
+func (m *MultiSubscriptionCache) SubscriptionsFor(ctx context.Context, location, name, resourceType, zone string) ([]string, error) {
+	views, err := m.Get(ctx, location, name, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]string, 0, len(views))
+	for subscriptionID := range views {
+		sku := views[subscriptionID]
+		if !sku.IsAvailable(location) || sku.IsRestricted(location) {
+			continue
+		}
+		if zone != "" {
+			// AvailabilityZones dereferences zone lists which may be nil.
+			_, restrictedZones, _ := sku.restrictionsFor(location)
+			if !sku.zones(location)[zone] || restrictedZones[zone] {
+				continue
+			}
+		}
+		subscriptions = append(subscriptions, subscriptionID)
+	}
+	sort.Strings(subscriptions)
+
+	return subscriptions, nil
+}
+
+// Merged returns a single view of a sku across all subscriptions. Its
+// restrictions only include what no subscription can deploy: the
+// location is restricted if every subscription is restricted there, and
+// a zone is restricted if every subscription is restricted in it.
+// Subscriptions which do not list the sku count as fully restricted.
+func (m *MultiSubscriptionCache) Merged(ctx context.Context, location, name, resourceType string) (SKU, bool, error) {
+	views, err := m.Get(ctx, location, name, resourceType)
+	if err != nil {
+		return SKU{}, false, err
+	}
+	if len(views) == 0 {
+		return SKU{}, false, nil
+	}
+
+	skus := make([]SKU, 0, len(views))
+	for _, subscriptionID := range m.Subscriptions() {
+		if sku, ok := views[subscriptionID]; ok {
+			skus = append(skus, sku)
+		}
+	}
+
+	// A subscription without the sku restricts everything, which leaves
+	// the intersection of restrictions unchanged, so it is skipped.
+	return MergeSKUs(location, skus...), true, nil
+}
+
+// MergeSKUs combines views of the same sku from different subscriptions
+// for one location. Zones are the union of all views. Restrictions are
+// the intersection: a location or zone restriction survives only when
+// every view is restricted there. Reason codes are kept when all
+// restricted views agree.
+func MergeSKUs(location string, skus ...SKU) SKU {
+	if len(skus) == 0 {
+		return SKU{}
+	}
+
+	merged := skus[0]
+	merged.LocationInfo = mergeLocationInfo(skus)
+
+	allZones := merged.zones(location)
+
+	var (
+		locationRestricted = true
+		restrictedZones    map[string]bool
+		reasonCode         compute.ResourceSkuRestrictionsReasonCode
+		reasonCodeSet      bool
+	)
+
+	agree := func(code compute.ResourceSkuRestrictionsReasonCode) {
+		if !reasonCodeSet {
+			reasonCode, reasonCodeSet = code, true
+		} else if reasonCode != code {
+			reasonCode = ""
+		}
+	}
+
+	for i := range skus {
+		restricted, zones, code := skus[i].restrictionsFor(location)
+		if !restricted {
+			locationRestricted = false
+		}
+		if restricted {
+			// Location restrictions cover every zone.
+			zones = make(map[string]bool, len(allZones))
+			for zone := range allZones {
+				zones[zone] = true
+			}
+		}
+		if restricted || len(zones) > 0 {
+			agree(code)
+		}
+		if restrictedZones == nil {
+			restrictedZones = zones
+			continue
+		}
+		for zone := range restrictedZones {
+			if !zones[zone] {
+				delete(restrictedZones, zone)
+			}
+		}
+	}
+
+	switch {
+	case locationRestricted:
+		merged.Restrictions = &[]compute.ResourceSkuRestrictions{
+			{
+				Type:       compute.Location,
+				Values:     &[]string{location},
+				ReasonCode: reasonCode,
+				RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+					Locations: &[]string{location},
+				},
+			},
+		}
+	case len(restrictedZones) > 0:
+		zones := make([]string, 0, len(restrictedZones))
+		for zone := range restrictedZones {
+			zones = append(zones, zone)
+		}
+		sort.Strings(zones)
+		merged.Restrictions = &[]compute.ResourceSkuRestrictions{
+			{
+				Type:       compute.Zone,
+				Values:     &[]string{location},
+				ReasonCode: reasonCode,
+				RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+					Locations: &[]string{location},
+					Zones:     &zones,
+				},
+			},
+		}
+	default:
+		merged.Restrictions = &[]compute.ResourceSkuRestrictions{}
+	}
+
+	return merged
+}
+
+// mergeLocationInfo returns the location info of all skus with the zones
+// of matching locations combined.
+func mergeLocationInfo(skus []SKU) *[]compute.ResourceSkuLocationInfo {
+	var (
+		order  []string
+		byName = make(map[string]*compute.ResourceSkuLocationInfo)
+		zones  = make(map[string]map[string]bool)
+	)
+
+	for i := range skus {
+		if skus[i].LocationInfo == nil {
+			continue
+		}
+		for _, info := range *skus[i].LocationInfo {
+			if info.Location == nil {
+				continue
+			}
+			key := strings.ToLower(*info.Location)
+			if _, ok := byName[key]; !ok {
+				info := info
+				order = append(order, key)
+				byName[key] = &info
+				zones[key] = make(map[string]bool)
+			}
+			if info.Zones != nil {
+				for _, zone := range *info.Zones {
+					zones[key][zone] = true
+				}
+			}
+		}
+	}
+
+	if len(order) == 0 {
+		return skus[0].LocationInfo
+	}
+
+	result := make([]compute.ResourceSkuLocationInfo, 0, len(order))
+	for _, key := range order {
+		info := *byName[key]
+		merged := make([]string, 0, len(zones[key]))
+		for zone := range zones[key] {
+			merged = append(merged, zone)
+		}
+		sort.Strings(merged)
+		info.Zones = &merged
+		result = append(result, info)
+	}
+
+	return &result
+}
+
+// zones returns the set of zones listed for a location, ignoring
+// restrictions.
+func (s *SKU) zones(location string) map[string]bool {
+	zones := make(map[string]bool)
+	if s.LocationInfo == nil {
+		return zones
+	}
+	for _, info := range *s.LocationInfo {
+		if info.Location == nil || !strings.EqualFold(*info.Location, location) || info.Zones == nil {
+			continue
+		}
+		for _, zone := range *info.Zones {
+			zones[zone] = true
+		}
+	}
+	return zones
+}
+
+// restrictionsFor summarizes the restrictions which apply to a location:
+// whether the whole location is restricted, the set of restricted zones,
+// and the reason code of the last matching restriction.
+func (s *SKU) restrictionsFor(location string) (bool, map[string]bool, compute.ResourceSkuRestrictionsReasonCode) {
+	var (
+		restricted bool
+		zones      = make(map[string]bool)
+		reasonCode compute.ResourceSkuRestrictionsReasonCode
+	)
+
+	if s.Restrictions == nil {
+		return restricted, zones, reasonCode
+	}
+
+	for _, restriction := range *s.Restrictions {
+		if !restrictionAppliesTo(restriction, location) {
+			continue
+		}
+		reasonCode = restriction.ReasonCode
+		switch restriction.Type {
+		case compute.Location:
+			restricted = true
+		case compute.Zone:
+			if restriction.RestrictionInfo == nil || restriction.RestrictionInfo.Zones == nil {
+				continue
+			}
+			for _, zone := range *restriction.RestrictionInfo.Zones {
+				zones[zone] = true
+			}
+		}
+	}
+
+	return restricted, zones, reasonCode
+}
+
+// restrictionAppliesTo returns true when a restriction lists the
+// location in its values or restriction info.
+func restrictionAppliesTo(restriction compute.ResourceSkuRestrictions, location string) bool {
+	if restriction.Values != nil {
+		for _, value := range *restriction.Values {
+			if strings.EqualFold(value, location) {
+				return true
+			}
+		}
+	}
+	if restriction.RestrictionInfo != nil && restriction.RestrictionInfo.Locations != nil {
+		for _, value := range *restriction.RestrictionInfo.Locations {
+			if strings.EqualFold(value, location) {
+				return true
+			}
+		}
+	}
+	return false
+}
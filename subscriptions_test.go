@@ -0,0 +1,216 @@
+package skewer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newFakeRestrictedSku(name, location string, restrictions ...compute.ResourceSkuRestrictions) compute.ResourceSku {
+	sku := newFakeLocationSku(name, location, "1", "2", "3")
+	sku.Restrictions = &restrictions
+	return sku
+}
+
+func newFakeZoneRestriction(location string, reason compute.ResourceSkuRestrictionsReasonCode, zones ...string) compute.ResourceSkuRestrictions {
+	return compute.ResourceSkuRestrictions{
+		Type:       compute.Zone,
+		Values:     &[]string{location},
+		ReasonCode: reason,
+		RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+			Locations: &[]string{location},
+			Zones:     &zones,
+		},
+	}
+}
+
+func newFakeLocationRestriction(location string, reason compute.ResourceSkuRestrictionsReasonCode) compute.ResourceSkuRestrictions {
+	return compute.ResourceSkuRestrictions{
+		Type:       compute.Location,
+		Values:     &[]string{location},
+		ReasonCode: reason,
+		RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+			Locations: &[]string{location},
+		},
+	}
+}
+
+func Test_MultiSubscriptionCache(t *testing.T) {
+	ctx := context.Background()
+	size := "Standard_D16s_v3"
+
+	views := map[string][]compute.ResourceSku{
+		"sub-a": {newFakeRestrictedSku(size, "eastus", newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2"))},
+		"sub-b": {newFakeRestrictedSku(size, "eastus")},
+		"sub-c": {newFakeRestrictedSku(size, "eastus", newFakeLocationRestriction("eastus", compute.NotAvailableForSubscription))},
+		"sub-d": {},
+	}
+
+	clients := make(map[string]ResourceClient, len(views))
+	for subscriptionID, skus := range views {
+		client, err := newSuccessfulFakeResourceClient([][]compute.ResourceSku{skus})
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients[subscriptionID] = client
+	}
+
+	cache, err := NewMultiSubscriptionCache(clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Preload(ctx, "eastus"); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		zone   string
+		expect []string
+	}{
+		"any zone": {
+			expect: []string{"sub-a", "sub-b"},
+		},
+		"zone 1": {
+			zone:   "1",
+			expect: []string{"sub-a", "sub-b"},
+		},
+		"zone 2": {
+			zone:   "2",
+			expect: []string{"sub-b"},
+		},
+		"zone 4": {
+			zone:   "4",
+			expect: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			got, err := cache.SubscriptionsFor(ctx, "eastus", size, VirtualMachines, tc.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	t.Run("nil zone lists should not be dereferenced", func(t *testing.T) {
+		nonZonal := newFakeLocationSku(size, "westus")
+		nonZonal.LocationInfo = &[]compute.ResourceSkuLocationInfo{{Location: to.StringPtr("westus")}}
+		restricted := newFakeLocationSku(size, "westus")
+		restricted.LocationInfo = &[]compute.ResourceSkuLocationInfo{{Location: to.StringPtr("westus")}}
+		restricted.Restrictions = &[]compute.ResourceSkuRestrictions{{
+			Type:            compute.Zone,
+			Values:          &[]string{"westus"},
+			ReasonCode:      compute.NotAvailableForSubscription,
+			RestrictionInfo: &compute.ResourceSkuRestrictionInfo{Locations: &[]string{"westus"}},
+		}}
+
+		clients := map[string]ResourceClient{}
+		for subscriptionID, sku := range map[string]compute.ResourceSku{"sub-a": nonZonal, "sub-b": restricted} {
+			client, err := newSuccessfulFakeResourceClient([][]compute.ResourceSku{{sku}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			clients[subscriptionID] = client
+		}
+		cache, err := NewMultiSubscriptionCache(clients)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := cache.SubscriptionsFor(ctx, "westus", size, VirtualMachines, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"sub-a", "sub-b"}, got); diff != "" {
+			t.Error(diff)
+		}
+		got, err = cache.SubscriptionsFor(ctx, "westus", size, VirtualMachines, "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{}, got); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("merged view should be unrestricted", func(t *testing.T) {
+		merged, found, err := cache.Merged(ctx, "eastus", "standard_d16s_v3", VirtualMachines)
+		if err != nil || !found {
+			t.Fatalf("expected to find merged sku, got found '%t' and error '%v'", found, err)
+		}
+		if merged.IsRestricted("eastus") || len(*merged.Restrictions) != 0 {
+			t.Errorf("expected no restrictions in merged view, got %v", *merged.Restrictions)
+		}
+	})
+
+	t.Run("missing sku should not be found", func(t *testing.T) {
+		if _, found, err := cache.Merged(ctx, "eastus", "Standard_Missing", VirtualMachines); found || err != nil {
+			t.Errorf("expected not to find missing sku, got found '%t' and error '%v'", found, err)
+		}
+	})
+}
+
+func Test_MergeSKUs(t *testing.T) {
+	zoneA := Wrap([]compute.ResourceSku{newFakeRestrictedSku("foo", "eastus",
+		newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2"))})[0]
+	zoneB := Wrap([]compute.ResourceSku{newFakeRestrictedSku("foo", "eastus",
+		newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "1", "2"))})[0]
+	zoneQuota := Wrap([]compute.ResourceSku{newFakeRestrictedSku("foo", "eastus",
+		newFakeZoneRestriction("eastus", compute.QuotaID, "2", "3"))})[0]
+	location := Wrap([]compute.ResourceSku{newFakeRestrictedSku("foo", "eastus",
+		newFakeLocationRestriction("eastus", compute.NotAvailableForSubscription))})[0]
+	unrestricted := Wrap([]compute.ResourceSku{newFakeRestrictedSku("foo", "eastus")})[0]
+
+	cases := map[string]struct {
+		skus   []SKU
+		expect []compute.ResourceSkuRestrictions
+	}{
+		"no views should be empty": {},
+		"single view should keep restrictions": {
+			skus:   []SKU{zoneA},
+			expect: []compute.ResourceSkuRestrictions{newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2")},
+		},
+		"zone restrictions should intersect": {
+			skus:   []SKU{zoneA, zoneB},
+			expect: []compute.ResourceSkuRestrictions{newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2")},
+		},
+		"location restriction should narrow to zones of other views": {
+			skus:   []SKU{location, zoneB},
+			expect: []compute.ResourceSkuRestrictions{newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "1", "2")},
+		},
+		"all location restrictions should be kept": {
+			skus:   []SKU{location, location},
+			expect: []compute.ResourceSkuRestrictions{newFakeLocationRestriction("eastus", compute.NotAvailableForSubscription)},
+		},
+		"disagreeing reason codes should be dropped": {
+			skus:   []SKU{zoneQuota, zoneB},
+			expect: []compute.ResourceSkuRestrictions{newFakeZoneRestriction("eastus", "", "2")},
+		},
+		"unrestricted view should lift restrictions": {
+			skus:   []SKU{location, zoneA, unrestricted},
+			expect: []compute.ResourceSkuRestrictions{},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			merged := MergeSKUs("eastus", tc.skus...)
+			var got []compute.ResourceSkuRestrictions
+			if merged.Restrictions != nil {
+				got = *merged.Restrictions
+			}
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package skewer
+
+// VirtualMachineSKU is a typed view of a virtual machine sku, with
+// capabilities parsed once. Optional capabilities, which Azure does not
+// list for every size, are zero when not listed.
+type VirtualMachineSKU struct {
+	Name   string
+	Family string
+
+	// VCPUs and MemoryGB are listed for every size.
+	VCPUs    int64
+	MemoryGB float64
+	// VCPUsAvailable is the number of vCPUs available to the guest. It
+	// defaults to VCPUs when not listed.
+	VCPUsAvailable int64
+	VCPUsPerCore   int64
+	ACUs           int64
+	GPUs           int64
+
+	MaxDataDiskCount     int64
+	MaxNetworkInterfaces int64
+	MaxResourceVolumeMB  int64
+	OSVhdSizeMB          int64
+	CachedDiskBytes      int64
+
+	UncachedDiskIOPS                             int64
+	UncachedDiskBytesPerSecond                   int64
+	CombinedTempDiskAndCachedIOPS                int64
+	CombinedTempDiskAndCachedReadBytesPerSecond  int64
+	CombinedTempDiskAndCachedWriteBytesPerSecond int64
+
+	// HyperVGenerations lists the supported generations, such as "V1"
+	// and "V2".
+	HyperVGenerations []string
+
+	PremiumIO                 bool
+	LowPriorityCapable        bool
+	RdmaEnabled               bool
+	AcceleratedNetworking     bool
+	EphemeralOSDiskSupported  bool
+	EncryptionAtHostSupported bool
+}
+
+// AsVirtualMachine returns a typed view of a virtual machine sku. It
+// errors if the sku is not a virtual machine, if vCPUs or MemoryGB are
+// not listed, or if any listed value is nil or could not be parsed.
+func (s *SKU) AsVirtualMachine() (VirtualMachineSKU, error) {
+	if !s.IsResourceType(VirtualMachines) {
+		return VirtualMachineSKU{}, &ErrResourceType{VirtualMachines, s.GetResourceType()}
+	}
+
+	r := &capabilityReader{list: s.Capabilities}
+	vm := VirtualMachineSKU{
+		Name:     s.GetName(),
+		Family:   derefString(s.Family),
+		VCPUs:    r.integer(VCPUs, true),
+		MemoryGB: r.float(MemoryGB, true),
+
+		VCPUsAvailable: r.integer(VCPUsAvailable, false),
+		VCPUsPerCore:   r.integer(VCPUsPerCore, false),
+		ACUs:           r.integer(ACUs, false),
+		GPUs:           r.integer(GPUs, false),
+
+		MaxDataDiskCount:     r.integer(MaxDataDiskCount, false),
+		MaxNetworkInterfaces: r.integer(MaxNetworkInterfaces, false),
+		MaxResourceVolumeMB:  r.integer(MaxResourceVolumeMB, false),
+		OSVhdSizeMB:          r.integer(OSVhdSizeMB, false),
+		CachedDiskBytes:      r.integer(CachedDiskBytes, false),
+
+		UncachedDiskIOPS:                             r.integer(UncachedDiskIOPS, false),
+		UncachedDiskBytesPerSecond:                   r.integer(UncachedDiskBytesPerSecond, false),
+		CombinedTempDiskAndCachedIOPS:                r.integer(CombinedTempDiskAndCachedIOPS, false),
+		CombinedTempDiskAndCachedReadBytesPerSecond:  r.integer(CombinedTempDiskAndCachedReadBytesPerSecond, false),
+		CombinedTempDiskAndCachedWriteBytesPerSecond: r.integer(CombinedTempDiskAndCachedWriteBytesPerSecond, false),
+
+		HyperVGenerations: r.values(HyperVGenerations),
+
+		PremiumIO:                 r.supported(PremiumIO),
+		LowPriorityCapable:        r.supported(LowPriorityCapable),
+		RdmaEnabled:               r.supported(RdmaEnabled),
+		AcceleratedNetworking:     r.supported(AcceleratedNetworking),
+		EphemeralOSDiskSupported:  r.supported(EphemeralOSDisk),
+		EncryptionAtHostSupported: r.supported(EncryptionAtHost),
+	}
+	if r.err != nil {
+		return VirtualMachineSKU{}, r.err
+	}
+
+	if _, ok := lookupCapability(s.Capabilities, VCPUsAvailable); !ok {
+		vm.VCPUsAvailable = vm.VCPUs
+	}
+
+	return vm, nil
+}
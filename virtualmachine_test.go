@@ -0,0 +1,153 @@
+package skewer
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testdataSKU returns the sku with a resource type, name and, when not
+// empty, size from the eastus testdata.
+func testdataSKU(t *testing.T, resourceType, name, size string) compute.ResourceSku {
+	t.Helper()
+	dataWrapper, err := newDataWrapper("./testdata/eastus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sku := range dataWrapper.Value {
+		if to.String(sku.ResourceType) == resourceType && to.String(sku.Name) == name &&
+			(size == "" || to.String(sku.Size) == size) {
+			return sku
+		}
+	}
+	t.Fatalf("expected to find %s sku %s %s in testdata", resourceType, name, size)
+	return compute.ResourceSku{}
+}
+
+func newFakeVMSku(capabilities ...compute.ResourceSkuCapabilities) compute.ResourceSku {
+	return compute.ResourceSku{
+		ResourceType: to.StringPtr(VirtualMachines),
+		Name:         to.StringPtr("Standard_Fake"),
+		Capabilities: &capabilities,
+	}
+}
+
+func Test_SKU_AsVirtualMachine(t *testing.T) {
+	cases := map[string]struct {
+		sku    compute.ResourceSku
+		expect VirtualMachineSKU
+		err    string
+	}{
+		"should parse general purpose size": {
+			sku: testdataSKU(t, VirtualMachines, "Standard_D4s_v3", ""),
+			expect: VirtualMachineSKU{
+				Name:                          "Standard_D4s_v3",
+				Family:                        "standardDSv3Family",
+				VCPUs:                         4,
+				MemoryGB:                      16,
+				VCPUsAvailable:                4,
+				VCPUsPerCore:                  2,
+				ACUs:                          160,
+				MaxDataDiskCount:              8,
+				MaxNetworkInterfaces:          2,
+				MaxResourceVolumeMB:           32768,
+				OSVhdSizeMB:                   1047552,
+				CachedDiskBytes:               107374182400,
+				UncachedDiskIOPS:              6400,
+				UncachedDiskBytesPerSecond:    100663296,
+				CombinedTempDiskAndCachedIOPS: 8000,
+				CombinedTempDiskAndCachedReadBytesPerSecond:  67108864,
+				CombinedTempDiskAndCachedWriteBytesPerSecond: 67108864,
+				HyperVGenerations:         []string{"V1", "V2"},
+				PremiumIO:                 true,
+				LowPriorityCapable:        true,
+				AcceleratedNetworking:     true,
+				EphemeralOSDiskSupported:  true,
+				EncryptionAtHostSupported: true,
+			},
+		},
+		"should parse gpu size": {
+			sku: testdataSKU(t, VirtualMachines, "Standard_NC6", ""),
+			expect: VirtualMachineSKU{
+				Name:                 "Standard_NC6",
+				Family:               "standardNCFamily",
+				VCPUs:                6,
+				MemoryGB:             56,
+				VCPUsAvailable:       6,
+				VCPUsPerCore:         1,
+				GPUs:                 1,
+				MaxDataDiskCount:     24,
+				MaxNetworkInterfaces: 2,
+				MaxResourceVolumeMB:  389120,
+				OSVhdSizeMB:          1047552,
+				HyperVGenerations:    []string{"V1"},
+				LowPriorityCapable:   true,
+			},
+		},
+		"should default available vCPUs and match names ignoring case": {
+			sku: newFakeVMSku(
+				compute.ResourceSkuCapabilities{Name: to.StringPtr("VCPUS"), Value: to.StringPtr("2")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr("memorygb"), Value: to.StringPtr("0.5")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr("rdmaenabled"), Value: to.StringPtr("true")},
+			),
+			expect: VirtualMachineSKU{
+				Name:           "Standard_Fake",
+				VCPUs:          2,
+				MemoryGB:       0.5,
+				VCPUsAvailable: 2,
+				RdmaEnabled:    true,
+			},
+		},
+		"should error for other resource types": {
+			sku: testdataSKU(t, Disks, "Premium_LRS", "P10"),
+			err: "expected sku of resource type 'virtualMachines', found 'disks'",
+		},
+		"should error when vCPUs are not listed": {
+			sku: newFakeVMSku(
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MemoryGB), Value: to.StringPtr("1")},
+			),
+			err: "vCPUsCapabilityNotFound",
+		},
+		"should error when an optional value is nil": {
+			sku: newFakeVMSku(
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(VCPUs), Value: to.StringPtr("1")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MemoryGB), Value: to.StringPtr("1")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(GPUs)},
+			),
+			err: "GPUsCapabilityValueNil",
+		},
+		"should error when an optional value could not be parsed": {
+			sku: newFakeVMSku(
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(VCPUs), Value: to.StringPtr("1")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MemoryGB), Value: to.StringPtr("1")},
+				compute.ResourceSkuCapabilities{Name: to.StringPtr(MaxDataDiskCount), Value: to.StringPtr("many")},
+			),
+			err: "MaxDataDiskCountCapabilityValueParse: failed to parse string 'many' as int64, error: 'strconv.ParseInt: parsing \"many\": invalid syntax'", // nolint:lll
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sku := SKU(tc.sku)
+			vm, err := sku.AsVirtualMachine()
+			if tc.err != "" {
+				if err == nil {
+					t.Fatalf("expected failure with error '%s' but did not occur", tc.err)
+				}
+				if diff := cmp.Diff(tc.err, err.Error()); diff != "" {
+					t.Error(diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected success but failure occurred with error '%s'", err)
+			}
+			if diff := cmp.Diff(tc.expect, vm); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package skewer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+)
+
+// SKUChangeType describes how a sku changed between two listings.
+type SKUChangeType string
+
+const (
+	// SKUAdded indicates a sku which was not previously listed.
+	SKUAdded SKUChangeType = "SKUAdded"
+	// SKURemoved indicates a sku which is no longer listed.
+	SKURemoved SKUChangeType = "SKURemoved"
+	// RestrictionAdded indicates a sku became restricted in a location,
+	// or in a zone of a location.
+	RestrictionAdded SKUChangeType = "RestrictionAdded"
+	// RestrictionLifted indicates a restriction no longer applies.
+	RestrictionLifted SKUChangeType = "RestrictionLifted"
+	// ZonesChanged indicates the zones listed for a location changed.
+	ZonesChanged SKUChangeType = "ZonesChanged"
+	// CapabilityChanged indicates a capability value changed, or a
+	// capability was added or removed.
+	CapabilityChanged SKUChangeType = "CapabilityChanged"
+)
+
+// SKUChangeEvent describes a single change to a sku between two
+// listings. It flattens an SKUDiff: restriction events are reported per
+// location and zone, and capability events per capability.
+type SKUChangeEvent struct {
+	Type SKUChangeType
+	// SKU is the sku after the change, or before it for SKURemoved.
+	SKU SKU
+	// Location is the location of a restriction or zone change.
+	Location string
+	// Zone is the zone of a zone restriction. It is empty for
+	// restrictions which apply to the whole location.
+	Zone string
+	// ReasonCode is the reason for a restriction.
+	ReasonCode compute.ResourceSkuRestrictionsReasonCode
+	// PreviousZones and Zones are the sorted zones listed for Location
+	// before and after a ZonesChanged event.
+	PreviousZones []string
+	Zones         []string
+	// Capability is the change of a CapabilityChanged event.
+	Capability CapabilityChange
+}
+
+// Watch returns a channel of changes to the cached data. Whenever a
+// refresh, or an Iterate call filling the cache, replaces the data, the
+// differences from the previous data are sent in order. Events are
+// queued for each watcher, so a slow reader never blocks refreshes. The
+// channel is closed once ctx is done.
+func (c *Cache) Watch(ctx context.Context) <-chan SKUChangeEvent {
+	w := &watcher{
+		out:    make(chan SKUChangeEvent),
+		notify: make(chan struct{}, 1),
+	}
+
+	c.watchMu.Lock()
+	if c.watchers == nil {
+		c.watchers = make(map[*watcher]struct{})
+	}
+	c.watchers[w] = struct{}{}
+	c.watchMu.Unlock()
+
+	go func() {
+		w.run(ctx)
+		c.watchMu.Lock()
+		delete(c.watchers, w)
+		c.watchMu.Unlock()
+		close(w.out)
+	}()
+
+	return w.out
+}
+
+// update is a replacement of the cached data whose differences are yet
+// to be sent to watchers. Updates are numbered with c.mu held, and their
+// events are sent in that order.
+type update struct {
+	seq          uint64
+	old, updated []SKU
+}
+
+// beginUpdate numbers a replacement of the cached data. It must be
+// called with c.mu held for writing, and followed by publish once the
+// lock is released.
+func (c *Cache) beginUpdate(old, updated []SKU) update {
+	u := update{seq: c.updates, old: old, updated: updated}
+	c.updates++
+	return u
+}
+
+// publish sends the differences of an update to all watchers. The diff
+// is computed without c.mu held, so it does not block queries, and its
+// events are queued only after those of every earlier update.
+func (c *Cache) publish(u update) {
+	var events []SKUChangeEvent
+	// Later updates wait for this one, so its turn ends even if the diff
+	// panics.
+	defer func() { c.sendInTurn(u.seq, events) }()
+
+	c.watchMu.Lock()
+	watching := len(c.watchers) > 0
+	c.watchMu.Unlock()
+	if watching {
+		events = Diff(u.old, u.updated).events()
+	}
+}
+
+// sendInTurn waits until the events of every update before seq were
+// queued, then queues events to all watchers.
+func (c *Cache) sendInTurn(seq uint64, events []SKUChangeEvent) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	if c.watchTurn.L == nil {
+		c.watchTurn.L = &c.watchMu
+	}
+	for c.published != seq {
+		c.watchTurn.Wait()
+	}
+	c.published++
+	c.watchTurn.Broadcast()
+
+	if len(events) == 0 {
+		return
+	}
+	for w := range c.watchers {
+		w.enqueue(events)
+	}
+}
+
+// watcher buffers events for a single Watch call.
+type watcher struct {
+	out    chan SKUChangeEvent
+	notify chan struct{}
+
+	mu    sync.Mutex
+	queue []SKUChangeEvent
+}
+
+// enqueue appends events to the queue without blocking.
+func (w *watcher) enqueue(events []SKUChangeEvent) {
+	w.mu.Lock()
+	w.queue = append(w.queue, events...)
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued events until ctx is done.
+func (w *watcher) run(ctx context.Context) {
+	for {
+		w.mu.Lock()
+		batch := w.queue
+		w.queue = nil
+		w.mu.Unlock()
+
+		for _, event := range batch {
+			select {
+			case w.out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-w.notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// events flattens a diff into events: removed skus, then added skus,
+// then the changes of each changed sku.
+func (d SKUDiff) events() []SKUChangeEvent {
+	var events []SKUChangeEvent
+	for i := range d.Removed {
+		events = append(events, SKUChangeEvent{Type: SKURemoved, SKU: d.Removed[i]})
+	}
+	for i := range d.Added {
+		events = append(events, SKUChangeEvent{Type: SKUAdded, SKU: d.Added[i]})
+	}
+	for _, change := range d.Changed {
+		for _, capability := range change.Capabilities {
+			events = append(events, SKUChangeEvent{
+				Type:       CapabilityChanged,
+				SKU:        change.New,
+				Capability: capability,
+			})
+		}
+		for _, zones := range change.Zones {
+			events = append(events, SKUChangeEvent{
+				Type:          ZonesChanged,
+				SKU:           change.New,
+				Location:      zones.Location,
+				PreviousZones: zones.Previous,
+				Zones:         zones.Current,
+			})
+		}
+		for _, restriction := range change.Restrictions {
+			events = append(events, SKUChangeEvent{
+				Type:       restriction.Type,
+				SKU:        change.New,
+				Location:   restriction.Location,
+				Zone:       restriction.Zone,
+				ReasonCode: restriction.ReasonCode,
+			})
+		}
+	}
+	return events
+}
@@ -0,0 +1,303 @@
+package skewer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+// change summarizes an event for comparison.
+type change struct {
+	Type          SKUChangeType
+	Name          string
+	Location      string
+	Zone          string
+	ReasonCode    compute.ResourceSkuRestrictionsReasonCode
+	PreviousZones []string
+	Zones         []string
+	Capability    CapabilityChange
+}
+
+func summarize(events []SKUChangeEvent) []change {
+	changes := []change{}
+	for i := range events {
+		changes = append(changes, change{
+			Type:          events[i].Type,
+			Name:          events[i].SKU.GetName(),
+			Location:      events[i].Location,
+			Zone:          events[i].Zone,
+			ReasonCode:    events[i].ReasonCode,
+			PreviousZones: events[i].PreviousZones,
+			Zones:         events[i].Zones,
+			Capability:    events[i].Capability,
+		})
+	}
+	return changes
+}
+
+func newFakeDiskSku(name, size string) compute.ResourceSku {
+	sku := newFakeLocationSku(name, "eastus")
+	sku.ResourceType = to.StringPtr(Disks)
+	sku.Size = to.StringPtr(size)
+	return sku
+}
+
+// nolint:funlen
+func Test_SKUDiff_events(t *testing.T) {
+	size := "Standard_NC6"
+
+	cases := map[string]struct {
+		old      []compute.ResourceSku
+		updated  []compute.ResourceSku
+		expected []change
+	}{
+		"should report nothing for identical listings": {
+			old:      []compute.ResourceSku{newFakeRestrictedSku(size, "eastus")},
+			updated:  []compute.ResourceSku{newFakeRestrictedSku(size, "eastus")},
+			expected: []change{},
+		},
+		"should report added and removed skus": {
+			old:     []compute.ResourceSku{newFakeLocationSku("Standard_D2s_v3", "eastus")},
+			updated: []compute.ResourceSku{newFakeLocationSku("Standard_D4s_v3", "eastus")},
+			expected: []change{
+				{Type: SKURemoved, Name: "Standard_D2s_v3"},
+				{Type: SKUAdded, Name: "Standard_D4s_v3"},
+			},
+		},
+		"should match skus case-insensitively": {
+			old:      []compute.ResourceSku{newFakeLocationSku("Standard_D2s_v3", "eastus")},
+			updated:  []compute.ResourceSku{newFakeLocationSku("standard_d2s_v3", "EastUS")},
+			expected: []change{},
+		},
+		"should distinguish disks by size": {
+			old:     []compute.ResourceSku{newFakeDiskSku("Premium_LRS", "P1"), newFakeDiskSku("Premium_LRS", "P2")},
+			updated: []compute.ResourceSku{newFakeDiskSku("Premium_LRS", "P2")},
+			expected: []change{
+				{Type: SKURemoved, Name: "Premium_LRS"},
+			},
+		},
+		"should report a zone restriction per zone": {
+			old:     []compute.ResourceSku{newFakeRestrictedSku(size, "eastus")},
+			updated: []compute.ResourceSku{newFakeRestrictedSku(size, "eastus", newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2", "1"))},
+			expected: []change{
+				{Type: RestrictionAdded, Name: size, Location: "eastus", Zone: "1", ReasonCode: compute.NotAvailableForSubscription},
+				{Type: RestrictionAdded, Name: size, Location: "eastus", Zone: "2", ReasonCode: compute.NotAvailableForSubscription},
+			},
+		},
+		"should report lifted restrictions": {
+			old: []compute.ResourceSku{newFakeRestrictedSku(size, "eastus",
+				newFakeLocationRestriction("eastus", compute.QuotaID),
+				newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "1", "3"),
+			)},
+			updated: []compute.ResourceSku{newFakeRestrictedSku(size, "eastus",
+				newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "1"),
+			)},
+			expected: []change{
+				{Type: RestrictionLifted, Name: size, Location: "eastus", ReasonCode: compute.QuotaID},
+				{Type: RestrictionLifted, Name: size, Location: "eastus", Zone: "3", ReasonCode: compute.NotAvailableForSubscription},
+			},
+		},
+		"should report changed reason codes": {
+			old:     []compute.ResourceSku{newFakeRestrictedSku(size, "eastus", newFakeLocationRestriction("eastus", compute.QuotaID))},
+			updated: []compute.ResourceSku{newFakeRestrictedSku(size, "eastus", newFakeLocationRestriction("eastus", compute.NotAvailableForSubscription))},
+			expected: []change{
+				{Type: RestrictionLifted, Name: size, Location: "eastus", ReasonCode: compute.QuotaID},
+				{Type: RestrictionAdded, Name: size, Location: "eastus", ReasonCode: compute.NotAvailableForSubscription},
+			},
+		},
+		"should report changed zones": {
+			old:     []compute.ResourceSku{newFakeLocationSku(size, "eastus", "3", "1")},
+			updated: []compute.ResourceSku{newFakeLocationSku(size, "eastus", "1", "2", "3")},
+			expected: []change{
+				{Type: ZonesChanged, Name: size, Location: "eastus", PreviousZones: []string{"1", "3"}, Zones: []string{"1", "2", "3"}},
+			},
+		},
+		"should report changed capabilities": {
+			old:     []compute.ResourceSku{withCapabilities(newFakeLocationSku(size, "eastus"), "MaxDataDiskCount", "8")},
+			updated: []compute.ResourceSku{withCapabilities(newFakeLocationSku(size, "eastus"), "MaxDataDiskCount", "16")},
+			expected: []change{
+				{Type: CapabilityChanged, Name: size, Capability: CapabilityChange{Name: "MaxDataDiskCount", OldValue: "8", NewValue: "16"}},
+			},
+		},
+		"should ignore zone order": {
+			old:      []compute.ResourceSku{newFakeLocationSku(size, "eastus", "3", "1", "2")},
+			updated:  []compute.ResourceSku{newFakeLocationSku(size, "eastus", "1", "2", "3")},
+			expected: []change{},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			got := summarize(Diff(Wrap(tc.old), Wrap(tc.updated)).events())
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("expected changes to match: %s", diff)
+			}
+		})
+	}
+}
+
+func receive(t *testing.T, events <-chan SKUChangeEvent, count int) []SKUChangeEvent {
+	t.Helper()
+	var received []SKUChangeEvent
+	for len(received) < count {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("expected %d events, channel closed after %d", count, len(received))
+			}
+			received = append(received, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %d events, received %d", count, len(received))
+		}
+	}
+	return received
+}
+
+func Test_Cache_Watch(t *testing.T) {
+	size := "Standard_NC6"
+	client := &fakeClient{
+		skus: []compute.ResourceSku{newFakeRestrictedSku(size, "eastus")},
+	}
+	cache, err := NewCache(context.Background(), WithClient(client), WithLocation("eastus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := cache.Watch(ctx)
+
+	// Refresh repeatedly without reading: watchers must not block refreshes.
+	listings := [][]compute.ResourceSku{
+		{newFakeRestrictedSku(size, "eastus")},
+		{newFakeRestrictedSku(size, "eastus", newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2"))},
+		{newFakeRestrictedSku(size, "eastus", newFakeZoneRestriction("eastus", compute.NotAvailableForSubscription, "2")), newFakeLocationSku("Standard_D2s_v3", "eastus")},
+		{newFakeRestrictedSku(size, "eastus")},
+	}
+	for _, skus := range listings {
+		client.skus = skus
+		if _, err := cache.Refresh(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []change{
+		{Type: RestrictionAdded, Name: size, Location: "eastus", Zone: "2", ReasonCode: compute.NotAvailableForSubscription},
+		{Type: SKUAdded, Name: "Standard_D2s_v3"},
+		{Type: SKURemoved, Name: "Standard_D2s_v3"},
+		{Type: RestrictionLifted, Name: size, Location: "eastus", Zone: "2", ReasonCode: compute.NotAvailableForSubscription},
+	}
+	if diff := cmp.Diff(expected, summarize(receive(t, events, len(expected)))); diff != "" {
+		t.Errorf("expected changes to match: %s", diff)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Errorf("expected no further events")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected channel to be closed after cancellation")
+	}
+
+	cache.watchMu.Lock()
+	defer cache.watchMu.Unlock()
+	if len(cache.watchers) != 0 {
+		t.Errorf("expected watcher to be removed, found %d", len(cache.watchers))
+	}
+}
+
+func Test_Cache_WatchConcurrentUpdates(t *testing.T) {
+	cache, err := NewStaticCache(Wrap([]compute.ResourceSku{newFakeLocationSku("initial", "eastus")}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := cache.Watch(ctx)
+
+	// Updates diff concurrently, and must still be sent in the order
+	// they replaced the data.
+	const updates = 50
+	generation := cache.nextGeneration()
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := Wrap([]compute.ResourceSku{newFakeLocationSku(fmt.Sprintf("sku-%d", i), "eastus")})
+			if !cache.storeComplete(data, time.Now(), generation) {
+				t.Error("expected the update to be stored")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	listed := map[string]bool{"initial": true}
+	for _, event := range receive(t, events, 2*updates) {
+		name := event.SKU.GetName()
+		switch event.Type {
+		case SKURemoved:
+			if !listed[name] {
+				t.Fatalf("expected removed sku %s to be listed", name)
+			}
+			delete(listed, name)
+		case SKUAdded:
+			listed[name] = true
+		default:
+			t.Fatalf("expected only skus to be added and removed, got %s", event.Type)
+		}
+	}
+	final := cache.List(context.Background())
+	if len(listed) != 1 || len(final) != 1 || !listed[final[0].GetName()] {
+		t.Errorf("expected events to lead to the cached skus %v, got %v", final, listed)
+	}
+}
+
+func Test_Cache_publishInOrder(t *testing.T) {
+	first := Wrap([]compute.ResourceSku{newFakeLocationSku("first", "eastus")})
+	second := Wrap([]compute.ResourceSku{newFakeLocationSku("second", "eastus")})
+	cache, err := NewStaticCache(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := cache.Watch(ctx)
+
+	cache.mu.Lock()
+	earlier := cache.beginUpdate(nil, first)
+	later := cache.beginUpdate(first, second)
+	cache.mu.Unlock()
+
+	// The later update finishes its diff first, and must wait for the
+	// earlier one.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.publish(later)
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected the later update to wait for the earlier one")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cache.publish(earlier)
+	<-done
+
+	expected := []change{
+		{Type: SKUAdded, Name: "first"},
+		{Type: SKURemoved, Name: "first"},
+		{Type: SKUAdded, Name: "second"},
+	}
+	if diff := cmp.Diff(expected, summarize(receive(t, events, len(expected)))); diff != "" {
+		t.Errorf("expected changes in order of the updates: %s", diff)
+	}
+}